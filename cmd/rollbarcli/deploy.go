@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+var deployCommands = map[string]func(rollbar.Client, []string) int{
+	"start":  deployStart,
+	"finish": deployFinish,
+	"list":   deployList,
+}
+
+func deploy(client rollbar.Client) int {
+	return runSubcommand(client, deployCommands)
+}
+
+func deployStart(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("deploy start", flag.ExitOnError)
+	environment := flags.String("environment", "", "Environment deployed to (required)")
+	revision := flags.String("revision", "", "Revision deployed (required)")
+	local_username := flags.String("local-username", os.Getenv("USER"), "Local user doing the deploy")
+	rollbar_username := flags.String("rollbar-username", "", "Rollbar user doing the deploy")
+	comment := flags.String("comment", "", "Deploy comment")
+	status := flags.String("status", string(rollbar.DEPLOY_STARTED), "Deploy status")
+	flags.Parse(args)
+
+	if *environment == "" || *revision == "" {
+		fmt.Fprintf(os.Stderr, "-environment and -revision are required\n")
+		flags.PrintDefaults()
+		return 1
+	}
+
+	response, err := client.RecordDeploy(
+		&rollbar.DeployRequest{
+			Environment:     *environment,
+			Revision:        *revision,
+			LocalUsername:   *local_username,
+			RollbarUsername: *rollbar_username,
+			Comment:         *comment,
+			Status:          rollbar.DeployStatus(*status),
+		},
+	)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	// Printed alone so scripts can capture it for 'deploy finish'
+	fmt.Printf("%d\n", response.Data.DeployID)
+	return 0
+}
+
+func deployFinish(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("deploy finish", flag.ExitOnError)
+	status := flags.String("status", string(rollbar.DEPLOY_SUCCEEDED), "Final deploy status (succeeded, failed, timed_out)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s finish [<flags>] <deploy_id>\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+	deploy_id, err := strconv.ParseUint(flags.Arg(0), 10, 64)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	response, err := client.SetDeployStatus(deploy_id, rollbar.DeployStatus(*status))
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got deploy: %s\n", response.Deploy.AsPrettyJSON())
+	return 0
+}
+
+func deployList(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("deploy list", flag.ExitOnError)
+	page := flags.Uint64("page", 1, "Page to get")
+	flags.Parse(args)
+
+	response, err := client.GetDeploysWithPage(*page)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got deploys: %s\n", response.AsPrettyJSON())
+	return 0
+}
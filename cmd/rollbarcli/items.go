@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Split a comma separated flag value, ignoring empty entries
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func listItems(client rollbar.Client) int {
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	status := flags.String("status", "", "Item status (active, resolved, muted, ...)")
+	levels := flags.String("level", "", "Comma separated levels")
+	environments := flags.String("environment", "", "Comma separated environments")
+	assigned_user := flags.String("assigned-user", "", "Assigned user's username, or 'unassigned'")
+	query := flags.String("query", "", "Search text")
+	page := flags.Uint64("page", 1, "Page to get")
+	all := flags.Bool("all", false, "Get all pages")
+	as_json := flags.Bool("json", false, "Output JSON instead of a table")
+	flags.Parse(os.Args[2:])
+
+	filter := &rollbar.ItemFilter{
+		Status:       rollbar.ItemStatus(*status),
+		Environments: splitList(*environments),
+		AssignedUser: *assigned_user,
+		Query:        *query,
+	}
+	for _, level := range splitList(*levels) {
+		filter.Levels = append(filter.Levels, rollbar.NotificationLevel(level))
+	}
+
+	var items []*rollbar.Item
+
+	if *all {
+		var err error
+		items, err = client.ItemsPager(filter, nil).Collect()
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return 1
+		}
+	} else {
+		response, err := client.ListItemsWithPage(filter, *page)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return 1
+		}
+		if response.ItemsResult != nil {
+			items = response.Items
+		}
+	}
+
+	if *as_json {
+		for _, item := range items {
+			fmt.Printf("%s\n", item.AsJSON())
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "COUNTER\tID\tLEVEL\tSTATUS\tENVIRONMENT\tOCCURRENCES\tTITLE\n")
+	for _, item := range items {
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%s\t%s\t%s\t%d\t%s\n",
+			item.Counter,
+			item.ID,
+			item.Level,
+			item.Status,
+			item.Environment,
+			item.TotalOccurrences,
+			item.Title,
+		)
+	}
+	w.Flush()
+	return 0
+}
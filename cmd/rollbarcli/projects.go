@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+var projectCommands = map[string]func(rollbar.Client, []string) int{
+	"list":   projectsList,
+	"get":    projectsGet,
+	"create": projectsCreate,
+	"delete": projectsDelete,
+}
+
+var tokenCommands = map[string]func(rollbar.Client, []string) int{
+	"list":   tokensList,
+	"create": tokensCreate,
+}
+
+func projects(client rollbar.Client) int {
+	return runSubcommand(client, projectCommands)
+}
+
+func tokens(client rollbar.Client) int {
+	return runSubcommand(client, tokenCommands)
+}
+
+// Parse the single id argument of a subcommand
+func parseIDArg(args []string, usage string) (uint64, bool) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s %s\n", os.Args[0], os.Args[1], usage)
+		return 0, false
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 0, false
+	}
+	return id, true
+}
+
+func projectsList(client rollbar.Client, args []string) int {
+	response, err := client.GetProjects()
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got projects: %s\n", response)
+	return 0
+}
+
+func projectsGet(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "get <project_id>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.GetProject(id)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got project: %s\n", response.Project.AsPrettyJSON())
+	return 0
+}
+
+func projectsCreate(client rollbar.Client, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s create <name>\n", os.Args[0], os.Args[1])
+		return 1
+	}
+
+	response, err := client.CreateProject(args[0])
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Created project: %s\n", response.Project.AsPrettyJSON())
+	return 0
+}
+
+func projectsDelete(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "delete <project_id>")
+	if !ok {
+		return 1
+	}
+
+	if err := client.DeleteProject(id); err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Deleted project %d\n", id)
+	return 0
+}
+
+func tokensList(client rollbar.Client, args []string) int {
+	project_id, ok := parseIDArg(args, "list <project_id>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.GetProjectAccessTokens(project_id)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got access tokens: %s\n", response)
+	return 0
+}
+
+func tokensCreate(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("tokens create", flag.ExitOnError)
+	name := flags.String("name", "", "Token name (required)")
+	scopes := flags.String("scopes", "", "Comma separated scopes: read,write,post_server_item,post_client_item (required)")
+	status := flags.String("status", "", "Token status: enabled or disabled")
+	window_count := flags.Uint64("rate-limit-count", 0, "Max calls per rate limit window (0 is unlimited)")
+	window_size := flags.Uint64("rate-limit-window", 0, "Rate limit window in seconds")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s create [<flags>] <project_id>\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *name == "" || *scopes == "" {
+		flags.Usage()
+		return 1
+	}
+	project_id, err := strconv.ParseUint(flags.Arg(0), 10, 64)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	token := &rollbar.AccessTokenRequest{
+		Name:                 *name,
+		Status:               *status,
+		RateLimitWindowCount: *window_count,
+		RateLimitWindowSize:  *window_size,
+	}
+	for _, scope := range splitList(*scopes) {
+		token.Scopes = append(token.Scopes, rollbar.AccessTokenScope(scope))
+	}
+
+	response, err := client.CreateProjectAccessToken(project_id, token)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Created access token: %s\n", response.AccessToken.AsPrettyJSON())
+	return 0
+}
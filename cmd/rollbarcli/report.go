@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+var reportCommands = map[string]func(rollbar.Client, []string) int{
+	"top":         reportTop,
+	"occurrences": reportOccurrences,
+	"activated":   reportActivated,
+}
+
+func report(client rollbar.Client) int {
+	return runSubcommand(client, reportCommands)
+}
+
+// Render hourly counts as a small bar chart
+func sparkline(counts []uint64) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+
+	var max uint64
+	for _, count := range counts {
+		if count > max {
+			max = count
+		}
+	}
+
+	var sb strings.Builder
+	for _, count := range counts {
+		if max == 0 {
+			sb.WriteRune(bars[0])
+			continue
+		}
+		sb.WriteRune(bars[count*uint64(len(bars)-1)/max])
+	}
+	return sb.String()
+}
+
+func reportTop(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("report top", flag.ExitOnError)
+	hours := flags.Uint64("hours", 24, "Hours of history to rank by")
+	environments := flags.String("environment", "", "Comma separated environments")
+	limit := flags.Int("limit", 0, "Show at most this many items")
+	as_json := flags.Bool("json", false, "Output JSON instead of a table")
+	flags.Parse(args)
+
+	response, err := client.GetTopActiveItems(
+		&rollbar.ReportFilter{
+			Environments: splitList(*environments),
+			Hours:        *hours,
+		},
+	)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	top_items := response.TopItems
+	if *limit > 0 && len(top_items) > *limit {
+		top_items = top_items[:*limit]
+	}
+
+	if *as_json {
+		for _, top_item := range top_items {
+			fmt.Printf("%s\n", top_item.AsJSON())
+		}
+		return 0
+	}
+
+	// Counters are what get_item_by_counter and the rollbar UI use
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "RANK\tCOUNTER\tLEVEL\tENVIRONMENT\tOCCURRENCES\tUNIQUE\tHOURLY\tTITLE\n")
+	for i, top_item := range top_items {
+		item := top_item.Item
+		if item == nil {
+			continue
+		}
+		fmt.Fprintf(
+			w,
+			"%d\t#%d\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			i+1,
+			item.Counter,
+			item.Level,
+			item.Environment,
+			item.Occurrences,
+			item.UniqueOccurrences,
+			sparkline(top_item.Counts),
+			item.Title,
+		)
+	}
+	w.Flush()
+	return 0
+}
+
+func reportOccurrences(client rollbar.Client, args []string) int {
+	return reportCounts(client, args, "occurrences", client.GetOccurrenceCounts)
+}
+
+func reportActivated(client rollbar.Client, args []string) int {
+	return reportCounts(client, args, "activated", client.GetActivatedCounts)
+}
+
+func reportCounts(client rollbar.Client, args []string, name string, get func(*rollbar.ReportFilter) (*rollbar.ReportCountsResponse, error)) int {
+	flags := flag.NewFlagSet("report "+name, flag.ExitOnError)
+	environments := flags.String("environment", "", "Comma separated environments")
+	bucket_size := flags.Duration("bucket", 24*time.Hour, "Size of each time bucket")
+	as_json := flags.Bool("json", false, "Output JSON instead of a table")
+	flags.Parse(args)
+
+	response, err := get(
+		&rollbar.ReportFilter{
+			Environments: splitList(*environments),
+			BucketSize:   *bucket_size,
+		},
+	)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	if *as_json {
+		fmt.Printf("%s\n", response.AsPrettyJSON())
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "TIME\tCOUNT\t\n")
+	for _, count := range response.Counts {
+		fmt.Fprintf(w, "%s\t%d\t\n", count.Timestamp.Format(time.RFC3339), count.Count)
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\t\n", response.Counts.Total())
+	w.Flush()
+	return 0
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/comstud/go-rollbar/rollbar"
 )
@@ -15,19 +17,45 @@ var commands = map[string]func(rollbar.Client) int{
 	"get_occurrence":       getOccurrence,
 	"get_item_occurrences": getItemOccurrences,
 	"get_occurrences":      getOccurrences,
+	"run":                  run,
+	"deploy":               deploy,
+	"list_items":           listItems,
+	"projects":             projects,
+	"tokens":               tokens,
+	"teams":                teams,
+	"users":                users,
+	"rql":                  rql,
+	"report":               report,
+	"tail":                 tail,
 }
 
 func main() {
 	apiToken := os.Getenv("ROLLBARCLI_API_TOKEN")
-	if apiToken == "" {
-		log.Fatal("Please set ROLLBARCLI_API_TOKEN environment variable")
-	}
 
 	client, err := rollbar.NewClient(apiToken)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// Scoped tokens can be given instead of, or as well as, a single token
+	opts := client.Options()
+	scopedTokens := map[string]*string{
+		"ROLLBARCLI_POST_SERVER_ITEM_TOKEN": &opts.PostServerItemToken,
+		"ROLLBARCLI_READ_TOKEN":             &opts.ReadToken,
+		"ROLLBARCLI_WRITE_TOKEN":            &opts.WriteToken,
+		"ROLLBARCLI_ACCOUNT_READ_TOKEN":     &opts.AccountReadToken,
+		"ROLLBARCLI_ACCOUNT_WRITE_TOKEN":    &opts.AccountWriteToken,
+	}
+
+	haveToken := apiToken != ""
+	for env, token := range scopedTokens {
+		*token = os.Getenv(env)
+		haveToken = haveToken || *token != ""
+	}
+	if !haveToken {
+		log.Fatal("Please set ROLLBARCLI_API_TOKEN environment variable")
+	}
+
 	if len(os.Args) < 2 {
 		fmt.Fprintf(os.Stderr, "Usage: %s <command> [<args>]\n", os.Args[0])
 		os.Exit(1)
@@ -52,6 +80,28 @@ func main() {
 	os.Exit(fn(client))
 }
 
+// Run a subcommand of the current command, e.g. 'deploy start'
+func runSubcommand(client rollbar.Client, subcommands map[string]func(rollbar.Client, []string) int) int {
+	keys := make([]string, 0, len(subcommands))
+	for k := range subcommands {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(os.Args) < 3 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s %s [<args>]\n", os.Args[0], os.Args[1], strings.Join(keys, "|"))
+		return 1
+	}
+
+	fn, ok := subcommands[os.Args[2]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown %s command. Valid commands are: %s\n", os.Args[1], strings.Join(keys, ","))
+		return 1
+	}
+
+	return fn(client, os.Args[3:])
+}
+
 func getItem(client rollbar.Client) int {
 	if len(os.Args) < 3 {
 		fmt.Fprintf(os.Stderr, "Usage: %s %s <identifier>\n", os.Args[0], os.Args[1])
@@ -68,10 +118,6 @@ func getItem(client rollbar.Client) int {
 		fmt.Printf("%s\n", err)
 		return 1
 	}
-	if response.IsError() {
-		fmt.Printf("Got error: %s\n", response.Message)
-		return 1
-	}
 
 	fmt.Printf("Got item: %s\n", response.Item.AsPrettyJSON())
 
@@ -94,10 +140,6 @@ func getItemByCounter(client rollbar.Client) int {
 		fmt.Printf("%s\n", err)
 		return 1
 	}
-	if response.IsError() {
-		fmt.Printf("Got error: %s\n", response.Message)
-		return 1
-	}
 	fmt.Printf("Got item: %s\n", response.Item.AsPrettyJSON())
 	return 0
 }
@@ -118,10 +160,6 @@ func getOccurrence(client rollbar.Client) int {
 		fmt.Printf("%s\n", err)
 		return 1
 	}
-	if response.IsError() {
-		fmt.Printf("Got error: %s\n", response.Message)
-		return 1
-	}
 	fmt.Printf("Got occurrence: %s\n", response.Occurrence.AsPrettyJSON())
 	return 0
 }
@@ -148,10 +186,6 @@ func getOccurrences(client rollbar.Client) int {
 		fmt.Printf("%s\n", err)
 		return 1
 	}
-	if response.IsError() {
-		fmt.Printf("Got error: %s\n", response.Message)
-		return 1
-	}
 	fmt.Printf("Got occurrences: %s\n", response.AsPrettyJSON())
 	return 0
 }
@@ -184,10 +218,6 @@ func getItemOccurrences(client rollbar.Client) int {
 		fmt.Printf("%s\n", err)
 		return 1
 	}
-	if response.IsError() {
-		fmt.Printf("Got error: %s\n", response.Message)
-		return 1
-	}
 	fmt.Printf("Got occurrences: %s\n", response.AsPrettyJSON())
 	return 0
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+func rql(client rollbar.Client) int {
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	format := flags.String("format", "table", "Output format (table, csv, json)")
+	force_refresh := flags.Bool("force-refresh", false, "Don't use cached results")
+	timeout := flags.Duration("timeout", 5*time.Minute, "Give up and cancel the job after this long")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [<flags>] \"<query>\"\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[2:])
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	switch *format {
+	case "table", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", *format)
+		return 1
+	}
+
+	// Interrupting cancels the job rather than leaving it running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	job_result, err := client.RunRQLQuery(
+		ctx,
+		flags.Arg(0),
+		&rollbar.RQLOptions{ForceRefresh: *force_refresh},
+	)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	result := job_result.Result
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	switch *format {
+	case "json":
+		for _, row := range result.RowMaps() {
+			data, _ := json.Marshal(row)
+			fmt.Printf("%s\n", data)
+		}
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(result.Columns)
+		for _, row := range result.Rows {
+			w.Write(rqlRowStrings(row))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			return 1
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\n", strings.Join(result.Columns, "\t"))
+		for _, row := range result.Rows {
+			fmt.Fprintf(w, "%s\n", strings.Join(rqlRowStrings(row), "\t"))
+		}
+		w.Flush()
+		fmt.Fprintf(os.Stderr, "%d row(s) in %.3fs\n", len(result.Rows), result.ExecutionTime)
+	}
+
+	return 0
+}
+
+// Format the values of a result row for display
+func rqlRowStrings(row []interface{}) []string {
+	strs := make([]string, len(row))
+	for i, value := range row {
+		switch v := value.(type) {
+		case nil:
+			strs[i] = ""
+		case string:
+			strs[i] = v
+		case float64:
+			// JSON numbers decode as floats. Show ids and counts as ints.
+			if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+				strs[i] = strconv.FormatInt(int64(v), 10)
+			} else {
+				strs[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		default:
+			data, _ := json.Marshal(v)
+			strs[i] = string(data)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Bytes of stderr kept for finding a Go panic
+const maxStderrCapture = 256 * 1024
+
+// Keeps the last 'max' bytes written to it
+type tailBuffer struct {
+	lock sync.Mutex
+	buf  []byte
+	max  int
+}
+
+func (self *tailBuffer) Write(p []byte) (int, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.buf = append(self.buf, p...)
+	if len(self.buf) > self.max {
+		self.buf = append(self.buf[:0], self.buf[len(self.buf)-self.max:]...)
+	}
+	return len(p), nil
+}
+
+func (self *tailBuffer) String() string {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return string(self.buf)
+}
+
+// Last 'n' lines of 's'
+func lastLines(s string, n int) string {
+	s = strings.TrimRight(s, "\n")
+	if n <= 0 {
+		return ""
+	}
+	idx := len(s)
+	for i := 0; i < n; i++ {
+		idx = strings.LastIndexByte(s[:idx], '\n')
+		if idx < 0 {
+			return s
+		}
+	}
+	return s[idx+1:]
+}
+
+func run(client rollbar.Client) int {
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	lines := flags.Int("lines", 50, "Number of lines of stderr to include")
+	level := flags.String("level", string(rollbar.LV_ERROR), "Notification level")
+	environment := flags.String("environment", "", "Environment (defaults to the client's)")
+	title := flags.String("title", "", "Notification title (defaults to the command and exit status)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [<flags>] -- <command> [<args>]\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[2:])
+
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return 1
+	}
+
+	stderr := &tailBuffer{max: maxStderrCapture}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return 127
+	}
+
+	// Pass signals on to the child
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	go func() {
+		for sig := range sigs {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	err := cmd.Wait()
+	duration := time.Since(start)
+	signal.Stop(sigs)
+	close(sigs)
+
+	exit_code := 0
+	signal_name := ""
+	if err != nil {
+		exit_err, ok := err.(*exec.ExitError)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			return 1
+		}
+		exit_code = exit_err.ExitCode()
+		if status, ok := exit_err.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			signal_name = status.Signal().String()
+			exit_code = 128 + int(status.Signal())
+		}
+	}
+
+	if exit_code == 0 {
+		return 0
+	}
+
+	command := strings.Join(args, " ")
+	output := stderr.String()
+
+	custom := rollbar.CustomInfo{
+		"command":     command,
+		"exit_code":   exit_code,
+		"duration":    duration.String(),
+		"stderr_tail": lastLines(output, *lines),
+	}
+	if signal_name != "" {
+		custom["signal"] = signal_name
+	}
+
+	message := *title
+	if message == "" {
+		if signal_name != "" {
+			message = fmt.Sprintf("%s killed by signal: %s", command, signal_name)
+		} else {
+			message = fmt.Sprintf("%s exited with code %d", command, exit_code)
+		}
+	}
+
+	var notif rollbar.Notification
+	if trace := rollbar.ParseGoTraceback(output); trace != nil {
+		trace_notif := client.NewTraceNotification(rollbar.NotificationLevel(*level), message, custom)
+		trace_notif.Trace = *trace
+		notif = trace_notif
+	} else {
+		notif = client.NewMessageNotification(rollbar.NotificationLevel(*level), message, custom)
+	}
+	if *environment != "" {
+		notif.SetEnvironment(*environment)
+	}
+
+	if _, err := client.SendNotification(notif); err != nil {
+		fmt.Fprintf(os.Stderr, "Error sending notification: %s\n", err)
+	}
+
+	return exit_code
+}
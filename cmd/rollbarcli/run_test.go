@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"empty", "", 3, ""},
+		{"none", "a\nb\n", 0, ""},
+		{"fewer lines", "a\nb\n", 3, "a\nb"},
+		{"exact", "a\nb\nc", 3, "a\nb\nc"},
+		{"more lines", "a\nb\nc\nd\n", 2, "c\nd"},
+		{"no trailing newline", "a\nb\nc", 1, "c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastLines(tt.s, tt.n); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	buf := &tailBuffer{max: 8}
+
+	n, err := buf.Write([]byte("hello "))
+	if n != 6 || err != nil {
+		t.Fatalf("Expected the whole write to succeed, got %d, %v", n, err)
+	}
+	buf.Write([]byte("world"))
+	if got := buf.String(); got != "lo world" {
+		t.Errorf("Expected the last 8 bytes, got %q", got)
+	}
+
+	buf.Write([]byte(strings.Repeat("x", 20)))
+	if got := buf.String(); got != strings.Repeat("x", 8) {
+		t.Errorf("Expected a large write to be cut to its end, got %q", got)
+	}
+}
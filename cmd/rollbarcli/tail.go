@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+func tail(client rollbar.Client) int {
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	item_counter := flags.Uint64("item", 0, "Only watch this item (by counter)")
+	levels := flags.String("level", "", "Comma separated levels")
+	environments := flags.String("environment", "", "Comma separated environments")
+	interval := flags.Duration("interval", rollbar.DEFAULT_WATCH_INTERVAL, "Time between polls")
+	since_id := flags.Uint64("since-id", 0, "Start after this occurrence id instead of now")
+	frames := flags.Int("frames", 3, "Stack frames to show per occurrence")
+	as_json := flags.Bool("json", false, "Output each occurrence as JSON")
+	flags.Parse(os.Args[2:])
+
+	filter := &rollbar.WatchFilter{
+		Environments: splitList(*environments),
+		SinceID:      *since_id,
+		Interval:     *interval,
+	}
+	for _, level := range splitList(*levels) {
+		filter.Levels = append(filter.Levels, rollbar.NotificationLevel(level))
+	}
+
+	if *item_counter != 0 {
+		response, err := client.GetItemByCounter(*item_counter)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return 1
+		}
+		filter.ItemID = response.ID
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Watching for new occurrences every %s...\n", *interval)
+
+	for event := range client.WatchOccurrences(ctx, filter) {
+		if event.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error polling occurrences: %s\n", event.Err)
+			continue
+		}
+		if *as_json {
+			fmt.Printf("%s\n", event.Occurrence.AsJSON())
+			continue
+		}
+		fmt.Print(formatOccurrence(event.Occurrence, *frames))
+	}
+
+	return 0
+}
+
+// Render an occurrence as a single summary line followed by the most
+// recent stack frames
+func formatOccurrence(occur *rollbar.Occurrence, max_frames int) string {
+	var sb strings.Builder
+
+	data := &occur.Data
+	fmt.Fprintf(
+		&sb,
+		"%s %-8s %s [%d] %s\n",
+		occur.Timestamp.Local().Format("2006-01-02 15:04:05"),
+		data.Level,
+		data.Environment,
+		occur.ID,
+		occurrenceTitle(occur),
+	)
+
+	trace := occurrenceTrace(&data.Body)
+	if trace == nil {
+		return sb.String()
+	}
+
+	// Frames are most recent last
+	shown := 0
+	for i := len(trace.Frames) - 1; i >= 0 && shown < max_frames; i-- {
+		frame := trace.Frames[i]
+		method := frame.Method
+		if method == "" {
+			method = "?"
+		}
+		fmt.Fprintf(&sb, "    at %s (%s:%d)\n", method, filepath.Base(frame.Filename), frame.Line)
+		shown++
+	}
+	if hidden := len(trace.Frames) - shown; hidden > 0 {
+		fmt.Fprintf(&sb, "    ... %d more\n", hidden)
+	}
+
+	return sb.String()
+}
+
+func occurrenceTitle(occur *rollbar.Occurrence) string {
+	data := &occur.Data
+	if data.Title != "" {
+		return data.Title
+	}
+
+	body := &data.Body
+	switch body.Kind() {
+	case rollbar.BODY_MESSAGE:
+		return strings.SplitN(body.Message.Body, "\n", 2)[0]
+	case rollbar.BODY_CRASH_REPORT:
+		return "Crash report"
+	}
+
+	trace := occurrenceTrace(body)
+	if trace == nil || trace.Exception == nil {
+		return "(no title)"
+	}
+	if trace.Exception.Message != "" {
+		return trace.Exception.Class + ": " + trace.Exception.Message
+	}
+	return trace.Exception.Class
+}
+
+// The trace of an occurrence body, or nil. For trace chains, this is the
+// first trace.
+func occurrenceTrace(body *rollbar.OccurrenceBody) *rollbar.NotifierTrace {
+	switch body.Kind() {
+	case rollbar.BODY_TRACE:
+		return body.Trace
+	case rollbar.BODY_TRACE_CHAIN:
+		if len(body.TraceChain) > 0 {
+			return body.TraceChain[0]
+		}
+	}
+	return nil
+}
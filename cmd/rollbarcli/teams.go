@@ -0,0 +1,658 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	"gopkg.in/yaml.v3"
+)
+
+var teamCommands = map[string]func(rollbar.Client, []string) int{
+	"list":           teamsList,
+	"get":            teamsGet,
+	"create":         teamsCreate,
+	"delete":         teamsDelete,
+	"users":          teamsUsers,
+	"add-user":       teamsAddUser,
+	"remove-user":    teamsRemoveUser,
+	"projects":       teamsProjects,
+	"add-project":    teamsAddProject,
+	"remove-project": teamsRemoveProject,
+	"invite":         teamsInvite,
+	"invites":        teamsInvites,
+	"sync":           teamsSync,
+}
+
+var userCommands = map[string]func(rollbar.Client, []string) int{
+	"list": usersList,
+	"get":  usersGet,
+}
+
+func teams(client rollbar.Client) int {
+	return runSubcommand(client, teamCommands)
+}
+
+func users(client rollbar.Client) int {
+	return runSubcommand(client, userCommands)
+}
+
+// Parse the two id arguments of a team membership subcommand
+func parseIDPairArgs(args []string, usage string) (uint64, uint64, bool) {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s %s\n", os.Args[0], os.Args[1], usage)
+		return 0, 0, false
+	}
+	ids := make([]uint64, 2)
+	for i, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return 0, 0, false
+		}
+		ids[i] = id
+	}
+	return ids[0], ids[1], true
+}
+
+func teamsList(client rollbar.Client, args []string) int {
+	response, err := client.GetTeams()
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tACCESS\tNAME\n")
+	for _, team := range response.Teams {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", team.ID, team.AccessLevel, team.Name)
+	}
+	w.Flush()
+	return 0
+}
+
+func teamsGet(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "get <team_id>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.GetTeam(id)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got team: %s\n", response.Team.AsPrettyJSON())
+	return 0
+}
+
+func teamsCreate(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("teams create", flag.ExitOnError)
+	access_level := flags.String("access-level", string(rollbar.TEAM_STANDARD), "Access level (standard, light, view)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s create [<flags>] <name>\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	response, err := client.CreateTeam(flags.Arg(0), rollbar.TeamAccessLevel(*access_level))
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Created team: %s\n", response.Team.AsPrettyJSON())
+	return 0
+}
+
+func teamsDelete(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "delete <team_id>")
+	if !ok {
+		return 1
+	}
+
+	if err := client.DeleteTeam(id); err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Deleted team %d\n", id)
+	return 0
+}
+
+func teamsUsers(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "users <team_id>")
+	if !ok {
+		return 1
+	}
+
+	user_ids, err := getTeamUserIDs(client, id)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	for _, user_id := range user_ids {
+		fmt.Printf("%d\n", user_id)
+	}
+	return 0
+}
+
+func teamsAddUser(client rollbar.Client, args []string) int {
+	team_id, user_id, ok := parseIDPairArgs(args, "add-user <team_id> <user_id>")
+	if !ok {
+		return 1
+	}
+
+	if err := client.AddTeamUser(team_id, user_id); err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func teamsRemoveUser(client rollbar.Client, args []string) int {
+	team_id, user_id, ok := parseIDPairArgs(args, "remove-user <team_id> <user_id>")
+	if !ok {
+		return 1
+	}
+
+	if err := client.RemoveTeamUser(team_id, user_id); err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func teamsProjects(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "projects <team_id>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.GetTeamProjects(id)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	for _, team_project := range response.TeamProjects {
+		fmt.Printf("%d\n", team_project.ProjectID)
+	}
+	return 0
+}
+
+func teamsAddProject(client rollbar.Client, args []string) int {
+	team_id, project_id, ok := parseIDPairArgs(args, "add-project <team_id> <project_id>")
+	if !ok {
+		return 1
+	}
+
+	if err := client.AddTeamProject(team_id, project_id); err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func teamsRemoveProject(client rollbar.Client, args []string) int {
+	team_id, project_id, ok := parseIDPairArgs(args, "remove-project <team_id> <project_id>")
+	if !ok {
+		return 1
+	}
+
+	if err := client.RemoveTeamProject(team_id, project_id); err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func teamsInvite(client rollbar.Client, args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s invite <team_id> <email>\n", os.Args[0], os.Args[1])
+		return 1
+	}
+	team_id, ok := parseIDArg(args[:1], "invite <team_id> <email>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.InviteTeamUser(team_id, args[1])
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Sent invite: %s\n", response.Invite.AsPrettyJSON())
+	return 0
+}
+
+func teamsInvites(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("teams invites", flag.ExitOnError)
+	page := flags.Uint64("page", 1, "Page to get")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s invites [<flags>] <team_id>\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	id, ok := parseIDArg(flags.Args(), "invites [<flags>] <team_id>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.GetTeamInvitesWithPage(id, *page)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got invites: %s\n", response)
+	return 0
+}
+
+func usersList(client rollbar.Client, args []string) int {
+	response, err := client.GetUsers()
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tUSERNAME\tEMAIL\n")
+	if response.UsersResult != nil {
+		for _, user := range response.Users {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", user.ID, user.Username, user.Email)
+		}
+	}
+	w.Flush()
+	return 0
+}
+
+func usersGet(client rollbar.Client, args []string) int {
+	id, ok := parseIDArg(args, "get <user_id>")
+	if !ok {
+		return 1
+	}
+
+	response, err := client.GetUser(id)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+	fmt.Printf("Got user: %s\n", response.User.AsPrettyJSON())
+	return 0
+}
+
+// Get the ids of all users in a team, across all pages
+func getTeamUserIDs(client rollbar.Client, team_id uint64) ([]uint64, error) {
+	var user_ids []uint64
+	for team_user, err := range client.TeamUsersPager(team_id, nil).All() {
+		if err != nil {
+			return nil, err
+		}
+		user_ids = append(user_ids, team_user.UserID)
+	}
+	return user_ids, nil
+}
+
+// Get the emails with pending invites to a team, across all pages
+func getPendingInvites(client rollbar.Client, team_id uint64) (map[string]bool, error) {
+	emails := map[string]bool{}
+	for invite, err := range client.TeamInvitesPager(team_id, nil).All() {
+		if err != nil {
+			return nil, err
+		}
+		if invite.Status == "pending" {
+			emails[strings.ToLower(invite.ToEmail)] = true
+		}
+	}
+	return emails, nil
+}
+
+// Desired membership of a team in a sync file
+type teamSpec struct {
+	// Used to create the team. A different level on an existing team is
+	// only warned about, as the API can't change it.
+	AccessLevel rollbar.TeamAccessLevel `json:"access_level" yaml:"access_level"`
+
+	// Usernames or emails. Emails without a rollbar user are invited.
+	Users []string `json:"users" yaml:"users"`
+
+	// Project names or ids
+	Projects []string `json:"projects" yaml:"projects"`
+}
+
+// Sync file contents, keyed by team name
+type teamsFile struct {
+	Teams map[string]*teamSpec `json:"teams" yaml:"teams"`
+}
+
+func loadTeamsFile(path string) (*teamsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &teamsFile{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, file)
+	default:
+		return nil, fmt.Errorf("Unknown file type for %s: expected .json, .yaml or .yml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+	}
+	return file, nil
+}
+
+// A single change found by a sync
+type syncChange struct {
+	desc  string
+	apply func(team_id uint64) error
+}
+
+// Changes needed to bring one team in line with its spec
+type teamSync struct {
+	name    string
+	team    *rollbar.Team
+	spec    *teamSpec
+	changes []*syncChange
+
+	// Differences that can't be changed through the API
+	warnings []string
+}
+
+func teamsSync(client rollbar.Client, args []string) int {
+	flags := flag.NewFlagSet("teams sync", flag.ExitOnError)
+	apply := flags.Bool("apply", false, "Apply the changes. Without this, only the diff is shown")
+	prune := flags.Bool("prune", false, "Also remove users and projects missing from the file")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s sync [<flags>] <file.yaml|file.json>\n", os.Args[0], os.Args[1])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	file, err := loadTeamsFile(flags.Arg(0))
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	syncs, err := planTeamsSync(client, file, *prune)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return 1
+	}
+
+	for _, sync := range syncs {
+		for _, warning := range sync.warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
+
+	num_changes := 0
+	for _, sync := range syncs {
+		if sync.team == nil {
+			fmt.Printf("+ team %s (%s)\n", sync.name, sync.spec.AccessLevel)
+			num_changes++
+		}
+		for _, change := range sync.changes {
+			fmt.Printf("%s\n", change.desc)
+			num_changes++
+		}
+	}
+
+	if num_changes == 0 {
+		fmt.Printf("Teams are in sync\n")
+		return 0
+	}
+	if !*apply {
+		fmt.Printf("%d change(s). Run again with -apply to make them.\n", num_changes)
+		return 0
+	}
+
+	failed := 0
+	for _, sync := range syncs {
+		if sync.team == nil {
+			response, err := client.CreateTeam(sync.name, sync.spec.AccessLevel)
+			if err != nil {
+				fmt.Printf("Error creating team %s: %s\n", sync.name, err)
+				failed += 1 + len(sync.changes)
+				continue
+			}
+			sync.team = response.Team
+		}
+		for _, change := range sync.changes {
+			if err := change.apply(sync.team.ID); err != nil {
+				fmt.Printf("Error applying '%s': %s\n", change.desc, err)
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d of %d change(s) failed\n", failed, num_changes)
+		return 1
+	}
+	fmt.Printf("Applied %d change(s)\n", num_changes)
+	return 0
+}
+
+// Work out the changes needed for every team in the file. Users and
+// projects missing from the file are only removed if 'prune' is set.
+func planTeamsSync(client rollbar.Client, file *teamsFile, prune bool) ([]*teamSync, error) {
+	teams_resp, err := client.GetTeams()
+	if err != nil {
+		return nil, err
+	}
+	teams_by_name := map[string]*rollbar.Team{}
+	for _, team := range teams_resp.Teams {
+		teams_by_name[team.Name] = team
+	}
+
+	users_resp, err := client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	users_by_name := map[string]*rollbar.User{}
+	users_by_id := map[uint64]*rollbar.User{}
+	if users_resp.UsersResult != nil {
+		for _, user := range users_resp.Users {
+			users_by_name[strings.ToLower(user.Username)] = user
+			if user.Email != "" {
+				users_by_name[strings.ToLower(user.Email)] = user
+			}
+			users_by_id[user.ID] = user
+		}
+	}
+
+	projects_resp, err := client.GetProjects()
+	if err != nil {
+		return nil, err
+	}
+	projects_by_name := map[string]*rollbar.Project{}
+	projects_by_id := map[uint64]*rollbar.Project{}
+	for _, project := range projects_resp.Projects {
+		projects_by_name[project.Name] = project
+		projects_by_id[project.ID] = project
+	}
+
+	names := make([]string, 0, len(file.Teams))
+	for name := range file.Teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var syncs []*teamSync
+
+	for _, name := range names {
+		spec := file.Teams[name]
+		if spec == nil {
+			spec = &teamSpec{}
+		}
+		// Existing teams are only checked if the file sets it
+		check_access_level := spec.AccessLevel != ""
+		if spec.AccessLevel == "" {
+			spec.AccessLevel = rollbar.TEAM_STANDARD
+		}
+		sync := &teamSync{name: name, team: teams_by_name[name], spec: spec}
+		syncs = append(syncs, sync)
+
+		if sync.team != nil && check_access_level && sync.team.AccessLevel != spec.AccessLevel {
+			// There's no API for it
+			sync.warnings = append(sync.warnings, fmt.Sprintf(
+				"team %s has access_level %s, not %s. Change it in rollbar's team settings.",
+				name, sync.team.AccessLevel, spec.AccessLevel,
+			))
+		}
+
+		current_users := map[uint64]bool{}
+		current_projects := map[uint64]bool{}
+		pending_invites := map[string]bool{}
+
+		if sync.team != nil {
+			user_ids, err := getTeamUserIDs(client, sync.team.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, user_id := range user_ids {
+				current_users[user_id] = true
+			}
+
+			team_projects, err := client.GetTeamProjects(sync.team.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, team_project := range team_projects.TeamProjects {
+				current_projects[team_project.ProjectID] = true
+			}
+
+			pending_invites, err = getPendingInvites(client, sync.team.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		wanted_users := map[uint64]bool{}
+		for _, name_or_email := range spec.Users {
+			user := users_by_name[strings.ToLower(name_or_email)]
+			if user == nil {
+				if !strings.Contains(name_or_email, "@") {
+					return nil, fmt.Errorf("Team %s: unknown user %s", name, name_or_email)
+				}
+				email := name_or_email
+				if pending_invites[strings.ToLower(email)] {
+					continue
+				}
+				sync.changes = append(sync.changes, &syncChange{
+					desc: fmt.Sprintf("+ invite %s -> team %s", email, name),
+					apply: func(team_id uint64) error {
+						_, err := client.InviteTeamUser(team_id, email)
+						return err
+					},
+				})
+				continue
+			}
+
+			wanted_users[user.ID] = true
+			if current_users[user.ID] {
+				continue
+			}
+			user_id := user.ID
+			sync.changes = append(sync.changes, &syncChange{
+				desc: fmt.Sprintf("+ user %s -> team %s", user.Username, name),
+				apply: func(team_id uint64) error {
+					return client.AddTeamUser(team_id, user_id)
+				},
+			})
+		}
+
+		wanted_projects := map[uint64]bool{}
+		for _, name_or_id := range spec.Projects {
+			project := projects_by_name[name_or_id]
+			if project == nil {
+				if id, err := strconv.ParseUint(name_or_id, 10, 64); err == nil {
+					project = projects_by_id[id]
+				}
+			}
+			if project == nil {
+				return nil, fmt.Errorf("Team %s: unknown project %s", name, name_or_id)
+			}
+
+			wanted_projects[project.ID] = true
+			if current_projects[project.ID] {
+				continue
+			}
+			project_id := project.ID
+			sync.changes = append(sync.changes, &syncChange{
+				desc: fmt.Sprintf("+ project %s -> team %s", project.Name, name),
+				apply: func(team_id uint64) error {
+					return client.AddTeamProject(team_id, project_id)
+				},
+			})
+		}
+
+		if !prune {
+			continue
+		}
+
+		for _, user_id := range sortedIDs(current_users) {
+			if wanted_users[user_id] {
+				continue
+			}
+			user_name := fmt.Sprintf("%d", user_id)
+			if user := users_by_id[user_id]; user != nil {
+				user_name = user.Username
+			}
+			sync.changes = append(sync.changes, &syncChange{
+				desc: fmt.Sprintf("- user %s <- team %s", user_name, name),
+				apply: func(team_id uint64) error {
+					return client.RemoveTeamUser(team_id, user_id)
+				},
+			})
+		}
+
+		for _, project_id := range sortedIDs(current_projects) {
+			if wanted_projects[project_id] {
+				continue
+			}
+			project_name := fmt.Sprintf("%d", project_id)
+			if project := projects_by_id[project_id]; project != nil {
+				project_name = project.Name
+			}
+			sync.changes = append(sync.changes, &syncChange{
+				desc: fmt.Sprintf("- project %s <- team %s", project_name, name),
+				apply: func(team_id uint64) error {
+					return client.RemoveTeamProject(team_id, project_id)
+				},
+			})
+		}
+	}
+
+	return syncs, nil
+}
+
+func sortedIDs(ids map[uint64]bool) []uint64 {
+	list := make([]uint64, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
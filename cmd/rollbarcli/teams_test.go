@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Client with one existing "light" team holding a user and a project
+type fakeTeamsClient struct {
+	rollbar.Client
+}
+
+func (self *fakeTeamsClient) GetTeams() (*rollbar.TeamsResponse, error) {
+	return &rollbar.TeamsResponse{Teams: []*rollbar.Team{
+		{ID: 1, Name: "backend", AccessLevel: rollbar.TEAM_LIGHT},
+	}}, nil
+}
+
+func (self *fakeTeamsClient) GetUsers() (*rollbar.UsersResponse, error) {
+	return &rollbar.UsersResponse{UsersResult: &rollbar.UsersResult{Users: []*rollbar.User{
+		{ID: 10, Username: "alice"},
+		{ID: 11, Username: "bob"},
+	}}}, nil
+}
+
+func (self *fakeTeamsClient) GetProjects() (*rollbar.ProjectsResponse, error) {
+	return &rollbar.ProjectsResponse{Projects: []*rollbar.Project{
+		{ID: 100, Name: "api"},
+		{ID: 101, Name: "web"},
+	}}, nil
+}
+
+func (self *fakeTeamsClient) TeamUsersPager(team_id uint64, options *rollbar.PagerOptions) *rollbar.Pager[*rollbar.TeamUser] {
+	return rollbar.NewPager(func(page uint64) ([]*rollbar.TeamUser, error) {
+		if page > 1 {
+			return nil, nil
+		}
+		return []*rollbar.TeamUser{{TeamID: team_id, UserID: 10}}, nil
+	}, nil, options)
+}
+
+func (self *fakeTeamsClient) TeamInvitesPager(team_id uint64, options *rollbar.PagerOptions) *rollbar.Pager[*rollbar.Invite] {
+	return rollbar.NewPager(func(page uint64) ([]*rollbar.Invite, error) {
+		return nil, nil
+	}, nil, options)
+}
+
+func (self *fakeTeamsClient) GetTeamProjects(team_id uint64) (*rollbar.TeamProjectsResponse, error) {
+	return &rollbar.TeamProjectsResponse{TeamProjects: []*rollbar.TeamProject{
+		{TeamID: team_id, ProjectID: 100},
+	}}, nil
+}
+
+func TestPlanTeamsSync(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  *teamSpec
+		prune bool
+		want  []string
+	}{
+		{
+			"in sync",
+			&teamSpec{AccessLevel: rollbar.TEAM_LIGHT, Users: []string{"alice"}, Projects: []string{"api"}},
+			false,
+			nil,
+		},
+		{
+			"access level not set",
+			&teamSpec{Users: []string{"alice"}, Projects: []string{"api"}},
+			false,
+			nil,
+		},
+		{
+			"access level only warned about",
+			&teamSpec{AccessLevel: rollbar.TEAM_STANDARD, Users: []string{"alice"}, Projects: []string{"api"}},
+			false,
+			nil,
+		},
+		{
+			"additions",
+			&teamSpec{Users: []string{"alice", "bob"}, Projects: []string{"api", "web"}},
+			false,
+			[]string{"+ user bob -> team backend", "+ project web -> team backend"},
+		},
+		{
+			"missing kept by default",
+			&teamSpec{},
+			false,
+			nil,
+		},
+		{
+			"missing pruned",
+			&teamSpec{},
+			true,
+			[]string{"- user alice <- team backend", "- project api <- team backend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &teamsFile{Teams: map[string]*teamSpec{"backend": tt.spec}}
+			syncs, err := planTeamsSync(&fakeTeamsClient{Client: rollbar.NewNOOPClient()}, file, tt.prune)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(syncs) != 1 || syncs[0].team == nil {
+				t.Fatalf("Expected a sync for the existing team, got %v", syncs)
+			}
+
+			var got []string
+			for _, change := range syncs[0].changes {
+				got = append(got, change.desc)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPlanTeamsSyncWarnsAboutAccessLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level rollbar.TeamAccessLevel
+		want  int
+	}{
+		{"not set", "", 0},
+		{"same", rollbar.TEAM_LIGHT, 0},
+		{"different", rollbar.TEAM_VIEW, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &teamsFile{Teams: map[string]*teamSpec{"backend": {AccessLevel: tt.level}}}
+			syncs, err := planTeamsSync(&fakeTeamsClient{Client: rollbar.NewNOOPClient()}, file, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(syncs[0].warnings) != tt.want {
+				t.Errorf("Expected %d warnings, got %q", tt.want, syncs[0].warnings)
+			}
+			if len(syncs[0].changes) != 0 {
+				t.Errorf("Expected the access level to be left out of the plan, got %d changes", len(syncs[0].changes))
+			}
+		})
+	}
+}
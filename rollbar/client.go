@@ -2,11 +2,14 @@ package rollbar
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	net_url "net/url"
+	"strings"
+	"time"
 )
 
 // client struct
@@ -16,39 +19,66 @@ type client struct {
 	accessToken     string
 	notifierName    string
 	notifierVersion string
+	telemetry       telemetryQueue
 
 	ClientOptions
 }
 
-func (self *client) decodeBody(http_resp *http.Response, resp interface{}) error {
-	if http_resp.StatusCode >= 200 && http_resp.StatusCode < 300 {
-		return json.NewDecoder(http_resp.Body).Decode(resp)
+// Bytes of request and response bodies included in debug logs
+const maxDebugBody = 4096
+
+// Header the access token is sent in
+const ACCESS_TOKEN_HEADER = "X-Rollbar-Access-Token"
+
+// Decode a response body into 'resp'. Error statuses, and successful
+// responses with a non-zero 'err', are returned as an *APIError.
+func (self *client) decodeBody(http_resp *http.Response, data []byte, read_err error, resp interface{}) error {
+	if read_err != nil {
+		if http_resp.StatusCode >= 200 && http_resp.StatusCode < 300 {
+			return read_err
+		}
+		api_err := newAPIError(http_resp, nil)
+		api_err.Message = fmt.Sprintf("<Error reading body: %s>", read_err)
+		return api_err
 	}
 
-	// Hrmph.
-	data, err := ioutil.ReadAll(http_resp.Body)
-	if err == nil {
-		return fmt.Errorf("Got code %d: %s\n", http_resp.StatusCode, string(data))
-	} else {
-		return fmt.Errorf("Got code %d: <Error reading body: %s>\n", http_resp.StatusCode, err)
+	if http_resp.StatusCode < 200 || http_resp.StatusCode >= 300 {
+		return newAPIError(http_resp, data)
+	}
+
+	// e.g. 204 from a DELETE
+	if len(data) == 0 {
+		return nil
 	}
-}
 
-func (self *client) httpCall(method string, url string, query net_url.Values, data interface{}, resp interface{}) error {
-	if query == nil {
-		query = make(net_url.Values)
+	base_resp := BaseAPIResponse{}
+	if err := json.Unmarshal(data, &base_resp); err == nil && base_resp.Err != 0 {
+		return newAPIError(http_resp, data)
 	}
 
-	query.Set("access_token", self.accessToken)
+	return json.Unmarshal(data, resp)
+}
+
+func (self *client) httpCall(scope tokenScope, method string, url string, query net_url.Values, data interface{}, resp interface{}) error {
+	return self.httpCallWithContext(context.Background(), scope, method, url, query, data, resp)
+}
+
+func (self *client) httpCallWithContext(ctx context.Context, scope tokenScope, method string, url string, query net_url.Values, data interface{}, resp interface{}) error {
+	token, err := self.tokenFor(scope)
+	if err != nil {
+		return err
+	}
+	return self.httpCallWithToken(ctx, token, method, url, query, data, resp)
+}
 
+func (self *client) httpCallWithToken(ctx context.Context, token string, method string, url string, query net_url.Values, data interface{}, resp interface{}) error {
 	if url != "" && url[0] != '/' {
 		url = self.apiBaseURL + "/" + url
 	} else {
 		url = self.apiBaseURL + url
 	}
 
-	query_str := query.Encode()
-	if query_str != "" {
+	if query_str := query.Encode(); query_str != "" {
 		url += "?" + query_str
 	}
 
@@ -63,35 +93,70 @@ func (self *client) httpCall(method string, url string, query net_url.Values, da
 		}
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(json_data))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(json_data))
 	if err != nil {
-		return err
+		return self.redactError(err, token)
 	}
 
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
+	req.Header.Set(ACCESS_TOKEN_HEADER, token)
+
+	if self.Debug {
+		self.debugf(token, "--> %s %s\n%s", method, url, self.debugBody(json_data))
+	}
+
+	start := time.Now()
 
 	http_resp, err := self.httpClient.Do(req)
 	if err != nil {
-		return err
+		if self.Debug {
+			self.debugf(token, "<-- %s %s failed: %s", method, url, err)
+		}
+		return self.redactError(err, token)
 	}
 
 	defer http_resp.Body.Close()
 
-	return self.decodeBody(http_resp, resp)
+	body, read_err := ioutil.ReadAll(http_resp.Body)
+
+	if self.Debug {
+		self.debugf(
+			token,
+			"<-- %d %s %s (%s)\n%s",
+			http_resp.StatusCode,
+			method,
+			url,
+			time.Since(start).Round(time.Millisecond),
+			self.debugBody(body),
+		)
+	}
+
+	err = self.decodeBody(http_resp, body, read_err, resp)
+	if api_err, ok := err.(*APIError); ok {
+		api_err.Message = self.redact(api_err.Message, token)
+	}
+	return err
+}
 
+func (self *client) httpGet(scope tokenScope, url string, query net_url.Values, resp interface{}) error {
+	return self.httpCall(scope, "GET", url, query, nil, resp)
 }
 
-func (self *client) httpGet(url string, query net_url.Values, resp interface{}) error {
-	return self.httpCall("GET", url, query, nil, resp)
+func (self *client) httpPatch(scope tokenScope, url string, data interface{}, resp interface{}) error {
+	return self.httpCall(scope, "PATCH", url, nil, data, resp)
 }
 
-func (self *client) httpPatch(url string, data interface{}, resp interface{}) error {
-	return self.httpCall("PATCH", url, nil, data, resp)
+func (self *client) httpPost(scope tokenScope, url string, data interface{}, resp interface{}) error {
+	return self.httpCall(scope, "POST", url, nil, data, resp)
 }
 
-func (self *client) httpPost(url string, data interface{}, resp interface{}) error {
-	return self.httpCall("POST", url, nil, data, resp)
+func (self *client) httpPut(scope tokenScope, url string, data interface{}, resp interface{}) error {
+	return self.httpCall(scope, "PUT", url, nil, data, resp)
+}
+
+func (self *client) httpDelete(scope tokenScope, url string, resp interface{}) error {
+	return self.httpCall(scope, "DELETE", url, nil, nil, resp)
 }
 
 // Get the client options
@@ -99,9 +164,9 @@ func (self *client) Options() *ClientOptions {
 	return &self.ClientOptions
 }
 
-// Get the base API URL
+// Get the base API URL, with any credentials in it redacted
 func (self *client) APIBaseURL() string {
-	return self.apiBaseURL
+	return self.redact(ScrubURL(self.apiBaseURL, nil), "")
 }
 
 // Set the base API URL
@@ -109,3 +174,74 @@ func (self *client) SetAPIBaseURL(base_url string) Client {
 	self.apiBaseURL = base_url
 	return self
 }
+
+// Replace access tokens in 's'. 'token' is the token used for the call,
+// which may not be one of the client's.
+func (self *client) redact(s string, token string) string {
+	tokens := []string{
+		token,
+		self.accessToken,
+		self.PostServerItemToken,
+		self.ReadToken,
+		self.WriteToken,
+		self.AccountReadToken,
+		self.AccountWriteToken,
+	}
+	for _, t := range tokens {
+		if t != "" {
+			s = strings.ReplaceAll(s, t, SCRUBBED_VALUE)
+		}
+	}
+	return s
+}
+
+// Error with access tokens removed from its message
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (self *redactedError) Error() string {
+	return self.msg
+}
+
+func (self *redactedError) Unwrap() error {
+	return self.err
+}
+
+func (self *client) redactError(err error, token string) error {
+	msg := err.Error()
+	if redacted := self.redact(msg, token); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
+
+func (self *client) debugf(token string, format string, args ...interface{}) {
+	if self.Logger != nil {
+		self.Logger.Print(self.redact(fmt.Sprintf("rollbar: "+format, args...), token))
+	}
+}
+
+// Body for debug logs, with values of ScrubFields scrubbed if it's JSON
+// (e.g. access tokens in project access token responses)
+func (self *client) debugBody(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err == nil && !dec.More() {
+		fields := self.ScrubFields
+		if fields == nil {
+			fields = DefaultScrubFields
+		}
+		scrubNested(fields, value)
+		if scrubbed, err := json.Marshal(value); err == nil {
+			body = scrubbed
+		}
+	}
+
+	if len(body) > maxDebugBody {
+		return string(body[:maxDebugBody]) + "..."
+	}
+	return string(body)
+}
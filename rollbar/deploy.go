@@ -0,0 +1,223 @@
+package rollbar
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type DeployStatus string
+
+const (
+	DEPLOY_STARTED   DeployStatus = DeployStatus("started")
+	DEPLOY_SUCCEEDED DeployStatus = DeployStatus("succeeded")
+	DEPLOY_FAILED    DeployStatus = DeployStatus("failed")
+	DEPLOY_TIMED_OUT DeployStatus = DeployStatus("timed_out")
+)
+
+// Deploy object as returned from API
+type Deploy struct {
+	ID            uint64       `json:"id"`
+	Project_id    uint64       `json:"project_id"`
+	Environment   string       `json:"environment"`
+	Revision      string       `json:"revision"`
+	LocalUsername string       `json:"local_username"`
+	Comment       string       `json:"comment"`
+	UserID        uint64       `json:"user_id"`
+	StartTime     JSONTime     `json:"start_time"`
+	FinishTime    JSONTime     `json:"finish_time"`
+	Status        DeployStatus `json:"status"`
+}
+
+// String representation in pretty JSON form
+func (self *Deploy) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Deploy as json string
+func (self *Deploy) AsJSON() string {
+	return asJSON(self)
+}
+
+// Deploy as pretty json
+func (self *Deploy) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Info used to record a deploy
+type DeployRequest struct {
+	// Required
+	Environment string `json:"environment"`
+	Revision    string `json:"revision"`
+
+	// Optional
+	RollbarUsername string       `json:"rollbar_username,omitempty"`
+	LocalUsername   string       `json:"local_username,omitempty"`
+	Comment         string       `json:"comment,omitempty"`
+	Status          DeployStatus `json:"status,omitempty"`
+}
+
+// Full API response for recording a deploy
+type RecordDeployResponse struct {
+	BaseAPIResponse
+	Data struct {
+		DeployID uint64 `json:"deploy_id"`
+	} `json:"data"`
+}
+
+// Full API response for a single deploy
+type DeployResponse struct {
+	BaseAPIResponse
+	*Deploy `json:"result"`
+}
+
+// Container for multiple deploys
+type DeploysResult struct {
+	Deploys []*Deploy `json:"deploys"`
+}
+
+// Full API response for multiple deploys
+type DeploysResponse struct {
+	rollbar  *client
+	pageSize int
+	BaseAPIResponse
+	Page           uint64
+	*DeploysResult `json:"result"`
+}
+
+// String representation of deploys response (pretty json)
+func (self *DeploysResponse) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Deploys response as a json string
+func (self *DeploysResponse) AsJSON() string {
+	if self.DeploysResult == nil {
+		return "[]"
+	}
+	return asJSON(self.Deploys)
+}
+
+// Deploys response as a pretty json string
+func (self *DeploysResponse) AsPrettyJSON() string {
+	if self.DeploysResult == nil {
+		return "[]"
+	}
+	return asPrettyJSON(self.Deploys)
+}
+
+// Does a deploys response have more pages? A page smaller than a full
+// one is the last.
+func (self *DeploysResponse) HasMorePages() bool {
+	if !self.IsSuccess() || self.DeploysResult == nil {
+		return false
+	}
+	page_size := self.pageSize
+	return pageHasMore(len(self.Deploys), &page_size)
+}
+
+// Get the next page of deploys
+func (self *DeploysResponse) GetNextPage() (*DeploysResponse, error) {
+	if !self.HasMorePages() {
+		return self, nil
+	}
+	if self.rollbar == nil {
+		return nil, errNoPagerClient
+	}
+	resp := &DeploysResponse{
+		rollbar:  self.rollbar,
+		pageSize: max(self.pageSize, len(self.Deploys)),
+		Page:     self.Page + 1,
+	}
+	return self.rollbar.getDeploys(resp)
+}
+
+// Record a deploy
+func (self *client) RecordDeploy(deploy *DeployRequest) (*RecordDeployResponse, error) {
+	if deploy.Environment == "" || deploy.Revision == "" {
+		return nil, errors.New("Environment and revision are required")
+	}
+
+	deploy_resp := &RecordDeployResponse{}
+
+	err := self.httpPost(scopePostServerItem, "/deploy", deploy, &deploy_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return deploy_resp, nil
+}
+
+// Update a deploy's status
+func (self *client) SetDeployStatus(id uint64, status DeployStatus) (*DeployResponse, error) {
+	deploy_update := map[string]interface{}{
+		"status": status,
+	}
+
+	deploy_resp := &DeployResponse{}
+
+	err := self.httpPatch(
+		scopeWrite,
+		fmt.Sprintf("/deploy/%d", id),
+		&deploy_update,
+		&deploy_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deploy_resp, nil
+}
+
+// Get a single deploy by its id
+func (self *client) GetDeploy(id uint64) (*DeployResponse, error) {
+	deploy_resp := &DeployResponse{}
+
+	err := self.httpGet(
+		scopeRead,
+		fmt.Sprintf("/deploy/%d", id),
+		nil,
+		&deploy_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deploy_resp, nil
+}
+
+func (self *client) getDeploys(resp *DeploysResponse) (*DeploysResponse, error) {
+	query := url.Values{
+		"page": []string{fmt.Sprintf("%d", resp.Page)},
+	}
+
+	err := self.httpGet(scopeRead, "/deploys", query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get first page of deploys
+func (self *client) GetDeploys() (*DeploysResponse, error) {
+	resp := &DeploysResponse{
+		rollbar:  self,
+		pageSize: DEPLOYS_PAGE_SIZE,
+		Page:     1,
+	}
+	return self.getDeploys(resp)
+}
+
+// Get a specific page of deploys
+func (self *client) GetDeploysWithPage(page uint64) (*DeploysResponse, error) {
+	if page == 0 {
+		return nil, errors.New("Page must be greater than 0")
+	}
+	resp := &DeploysResponse{
+		rollbar:  self,
+		pageSize: DEPLOYS_PAGE_SIZE,
+		Page:     page,
+	}
+	return self.getDeploys(resp)
+}
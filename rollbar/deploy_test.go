@@ -0,0 +1,110 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecordDeploy(t *testing.T) {
+	var method, path, body string
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		data, _ := io.ReadAll(req.Body)
+		method, path, body = req.Method, req.URL.Path, string(data)
+		w.Write([]byte(`{"err": 0, "data": {"deploy_id": 42}}`))
+	})
+
+	if _, err := c.RecordDeploy(&DeployRequest{Environment: "production"}); err == nil {
+		t.Errorf("Expected an error without a revision")
+	}
+	if method != "" {
+		t.Errorf("Expected nothing sent for an invalid deploy")
+	}
+
+	resp, err := c.RecordDeploy(&DeployRequest{
+		Environment:   "production",
+		Revision:      "abc123",
+		LocalUsername: "alice",
+		Status:        DEPLOY_STARTED,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if resp.Data.DeployID != 42 {
+		t.Errorf("Expected deploy ID 42, got %d", resp.Data.DeployID)
+	}
+	if method != "POST" || !strings.HasSuffix(path, "/deploy") {
+		t.Errorf("Expected POST /deploy, got %s %s", method, path)
+	}
+	want := `{"environment":"production","revision":"abc123","local_username":"alice","status":"started"}`
+	if body != want {
+		t.Errorf("Expected body %s, got %s", want, body)
+	}
+}
+
+func TestSetDeployStatus(t *testing.T) {
+	var method, path, body string
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		data, _ := io.ReadAll(req.Body)
+		method, path, body = req.Method, req.URL.Path, string(data)
+		w.Write([]byte(`{"err": 0, "result": {"id": 42, "status": "succeeded"}}`))
+	})
+
+	resp, err := c.SetDeployStatus(42, DEPLOY_SUCCEEDED)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if method != "PATCH" || !strings.HasSuffix(path, "/deploy/42") || body != `{"status":"succeeded"}` {
+		t.Errorf("Expected PATCH /deploy/42 with the status, got %s %s %s", method, path, body)
+	}
+	if resp.Deploy == nil || resp.Status != DEPLOY_SUCCEEDED {
+		t.Errorf("Expected the updated deploy, got %+v", resp.Deploy)
+	}
+}
+
+func TestGetDeploysPaging(t *testing.T) {
+	// Two full pages and a partial one
+	const total = 2*DEPLOYS_PAGE_SIZE + 5
+	var pages []string
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		page := req.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		var n int
+		fmt.Sscan(page, &n)
+		deploys := []*Deploy{}
+		for id := (n-1)*DEPLOYS_PAGE_SIZE + 1; id <= min(n*DEPLOYS_PAGE_SIZE, total); id++ {
+			deploys = append(deploys, &Deploy{ID: uint64(id)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"err":    0,
+			"result": map[string]interface{}{"deploys": deploys},
+		})
+	})
+
+	resp, err := c.GetDeploys()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	count := len(resp.Deploys)
+	for resp.HasMorePages() {
+		if resp, err = resp.GetNextPage(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		count += len(resp.Deploys)
+	}
+
+	if count != total {
+		t.Errorf("Expected %d deploys, got %d", total, count)
+	}
+	if fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("Expected pages 1 to 3 to be fetched, got %v", pages)
+	}
+
+	if _, err := c.GetDeploysWithPage(0); err == nil {
+		t.Errorf("Expected an error for page 0")
+	}
+}
@@ -0,0 +1,155 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bytes of a non-JSON error body kept in APIError.Message
+const maxErrorBody = 1024
+
+// Rate limit info sent with API responses
+type RateLimit struct {
+	// Requests allowed per period
+	Limit int
+
+	// Requests left in the current period
+	Remaining int
+
+	// When the current period ends
+	Reset time.Time
+}
+
+// Parse rate limit headers, returning nil if there are none
+func parseRateLimit(header http.Header) *RateLimit {
+	limit := header.Get("X-Rate-Limit-Limit")
+	remaining := header.Get("X-Rate-Limit-Remaining")
+	if limit == "" && remaining == "" {
+		return nil
+	}
+
+	rate_limit := &RateLimit{}
+	rate_limit.Limit, _ = strconv.Atoi(limit)
+	rate_limit.Remaining, _ = strconv.Atoi(remaining)
+	if secs, err := strconv.ParseInt(header.Get("X-Rate-Limit-Remaining-Seconds"), 10, 64); err == nil {
+		rate_limit.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+	} else if reset, err := strconv.ParseInt(header.Get("X-Rate-Limit-Reset"), 10, 64); err == nil {
+		rate_limit.Reset = time.Unix(reset, 0)
+	}
+	return rate_limit
+}
+
+// Error returned by client methods when the API returns an error, either
+// with an HTTP error status or with a non-zero 'err' in the response.
+type APIError struct {
+	// HTTP status code
+	StatusCode int
+
+	// Rollbar's 'err' code from the response body. 0 if the body
+	// wasn't a rollbar response.
+	Code int
+
+	// Rollbar's error message, or the start of the body if it wasn't a
+	// rollbar response
+	Message string
+
+	Method string
+	Path   string
+
+	// Request id sent back by the API, for support requests
+	RequestID string
+
+	// Rate limit info, if the API sent any
+	RateLimit *RateLimit
+}
+
+func (self *APIError) Error() string {
+	msg := fmt.Sprintf("Got code %d from %s %s", self.StatusCode, self.Method, self.Path)
+	if self.Message != "" {
+		msg += ": " + self.Message
+	}
+	if self.RequestID != "" {
+		msg += " (request id " + self.RequestID + ")"
+	}
+	return msg
+}
+
+// Build an APIError from a response and its body
+func newAPIError(http_resp *http.Response, body []byte) *APIError {
+	api_err := &APIError{
+		StatusCode: http_resp.StatusCode,
+		RequestID:  http_resp.Header.Get("X-Request-Id"),
+		RateLimit:  parseRateLimit(http_resp.Header),
+	}
+	if http_resp.Request != nil {
+		api_err.Method = http_resp.Request.Method
+		api_err.Path = http_resp.Request.URL.Path
+	}
+
+	base_resp := BaseAPIResponse{}
+	if err := json.Unmarshal(body, &base_resp); err == nil && (base_resp.Err != 0 || base_resp.Message != "") {
+		api_err.Code = base_resp.Err
+		api_err.Message = base_resp.Message
+	} else {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > maxErrorBody {
+			msg = msg[:maxErrorBody] + "..."
+		}
+		api_err.Message = msg
+	}
+	return api_err
+}
+
+// Get the APIError from an error returned by a client method
+func AsAPIError(err error) (*APIError, bool) {
+	var api_err *APIError
+	if errors.As(err, &api_err) {
+		return api_err, true
+	}
+	return nil, false
+}
+
+// Is the error from the API saying something doesn't exist?
+func IsNotFound(err error) bool {
+	api_err, ok := AsAPIError(err)
+	return ok && api_err.StatusCode == http.StatusNotFound
+}
+
+// Is the error from the API rejecting the access token? This includes
+// tokens without the scope needed for the call.
+func IsUnauthorized(err error) bool {
+	api_err, ok := AsAPIError(err)
+	return ok && (api_err.StatusCode == http.StatusUnauthorized || api_err.StatusCode == http.StatusForbidden)
+}
+
+// Is the error from the API rate limiting us?
+func IsRateLimited(err error) bool {
+	api_err, ok := AsAPIError(err)
+	return ok && api_err.StatusCode == http.StatusTooManyRequests
+}
+
+// Could the call succeed if tried again later? True for rate limiting,
+// server errors and network timeouts.
+func IsRetryable(err error) bool {
+	if api_err, ok := AsAPIError(err); ok {
+		switch api_err.StatusCode {
+		case http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var net_err net.Error
+	return errors.As(err, &net_err) && net_err.Timeout()
+}
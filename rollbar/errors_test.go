@@ -0,0 +1,139 @@
+package rollbar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		header        map[string]string
+		body          string
+		wantCode      int
+		wantMessage   string
+		wantNotFound  bool
+		wantUnauth    bool
+		wantRateLimit bool
+		wantRetry     bool
+	}{
+		{
+			"not found",
+			http.StatusNotFound, nil,
+			`{"err": 1, "message": "Item not found"}`,
+			1, "Item not found",
+			true, false, false, false,
+		},
+		{
+			"unauthorized",
+			http.StatusUnauthorized, nil,
+			`{"err": 1, "message": "invalid access token"}`,
+			1, "invalid access token",
+			false, true, false, false,
+		},
+		{
+			"missing scope",
+			http.StatusForbidden, nil,
+			`{"err": 1, "message": "insufficient privileges"}`,
+			1, "insufficient privileges",
+			false, true, false, false,
+		},
+		{
+			"rate limited",
+			http.StatusTooManyRequests,
+			map[string]string{"X-Rate-Limit-Limit": "5000", "X-Rate-Limit-Remaining": "0", "X-Rate-Limit-Remaining-Seconds": "30"},
+			`{"err": 1, "message": "rate limited"}`,
+			1, "rate limited",
+			false, false, true, true,
+		},
+		{
+			"server error",
+			http.StatusBadGateway, nil,
+			"<html>Bad Gateway</html>",
+			0, "<html>Bad Gateway</html>",
+			false, false, false, true,
+		},
+		{
+			"long body",
+			http.StatusInternalServerError, nil,
+			strings.Repeat("x", 2*maxErrorBody),
+			0, strings.Repeat("x", maxErrorBody) + "...",
+			false, false, false, true,
+		},
+		{
+			"err in ok response",
+			http.StatusOK, nil,
+			`{"err": 1, "message": "nope"}`,
+			1, "nope",
+			false, false, false, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("X-Request-Id", "req-1")
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := c.GetItem(1)
+			api_err, ok := AsAPIError(fmt.Errorf("wrapped: %w", err))
+			if !ok {
+				t.Fatalf("Expected an *APIError, got %v", err)
+			}
+			if api_err.StatusCode != tt.status || api_err.Code != tt.wantCode || api_err.Message != tt.wantMessage {
+				t.Errorf("Expected %d/%d %q, got %d/%d %q",
+					tt.status, tt.wantCode, tt.wantMessage, api_err.StatusCode, api_err.Code, api_err.Message)
+			}
+			if api_err.Method != "GET" || !strings.HasSuffix(api_err.Path, "/item/1") || api_err.RequestID != "req-1" {
+				t.Errorf("Expected the request details, got %s %s (%s)", api_err.Method, api_err.Path, api_err.RequestID)
+			}
+
+			if IsNotFound(err) != tt.wantNotFound || IsUnauthorized(err) != tt.wantUnauth ||
+				IsRateLimited(err) != tt.wantRateLimit || IsRetryable(err) != tt.wantRetry {
+				t.Errorf("Expected not found=%v unauthorized=%v rate limited=%v retryable=%v, got %v %v %v %v",
+					tt.wantNotFound, tt.wantUnauth, tt.wantRateLimit, tt.wantRetry,
+					IsNotFound(err), IsUnauthorized(err), IsRateLimited(err), IsRetryable(err))
+			}
+
+			if tt.header == nil {
+				if api_err.RateLimit != nil {
+					t.Errorf("Expected no rate limit info, got %+v", api_err.RateLimit)
+				}
+				return
+			}
+			rate_limit := api_err.RateLimit
+			if rate_limit == nil || rate_limit.Limit != 5000 || rate_limit.Remaining != 0 {
+				t.Fatalf("Expected rate limit info, got %+v", rate_limit)
+			}
+			if until := time.Until(rate_limit.Reset); until <= 0 || until > 30*time.Second {
+				t.Errorf("Expected the limit to reset in 30s, got %s", until)
+			}
+		})
+	}
+}
+
+func TestIsRetryableTimeout(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+	c.httpClient.Timeout = 10 * time.Millisecond
+
+	_, err := c.GetItem(1)
+	if err == nil || !IsRetryable(err) {
+		t.Errorf("Expected a retryable timeout, got %v", err)
+	}
+	if IsRetryable(context.Canceled) || IsRetryable(errors.New("boom")) {
+		t.Errorf("Expected other errors not to be retryable")
+	}
+}
@@ -0,0 +1,109 @@
+package rollbargrpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func recordCall(client rollbar.Client, cc *grpc.ClientConn, method string, err error, start time.Time) {
+	code := status.Code(err)
+	level := rollbar.LV_INFO
+	if code != codes.OK {
+		level = rollbar.LV_WARNING
+	}
+
+	entry := rollbar.NewNetworkTelemetry(level, "grpc", method, cc.Target()+method, int(code), start, time.Now())
+	entry.Body["grpc_code"] = code.String()
+	client.AddTelemetry(entry)
+}
+
+// Unary client interceptor that records outgoing calls as telemetry
+func UnaryClientInterceptor(client rollbar.Client) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordCall(client, cc, method, err, start)
+		return err
+	}
+}
+
+// Stream client interceptor that records outgoing streams as telemetry
+// once they finish: after the response for streams without server
+// streaming, otherwise once RecvMsg() fails (io.EOF meaning success), or
+// when ctx is done
+func StreamClientInterceptor(client rollbar.Client) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			recordCall(client, cc, method, err, start)
+			return nil, err
+		}
+		stream := &clientStream{
+			ClientStream:  cs,
+			serverStreams: desc.ServerStreams,
+			record: func(err error) {
+				recordCall(client, cc, method, err, start)
+			},
+		}
+		// If ctx is already done, this may finish the stream before
+		// stopWatching is set, which is fine as it has already fired
+		stop := context.AfterFunc(ctx, func() {
+			stream.finish(status.FromContextError(ctx.Err()).Err())
+		})
+		stream.lock.Lock()
+		stream.stopWatching = stop
+		stream.lock.Unlock()
+		return stream, nil
+	}
+}
+
+// ClientStream that records the call when it finishes
+type clientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	record        func(err error)
+	once          sync.Once
+
+	lock         sync.Mutex
+	stopWatching func() bool
+}
+
+func (self *clientStream) finish(err error) {
+	if err == io.EOF {
+		err = nil
+	}
+	self.once.Do(func() {
+		self.lock.Lock()
+		stop := self.stopWatching
+		self.lock.Unlock()
+		if stop != nil {
+			stop()
+		}
+		self.record(err)
+	})
+}
+
+func (self *clientStream) SendMsg(m interface{}) error {
+	err := self.ClientStream.SendMsg(m)
+	if err != nil && err != io.EOF {
+		self.finish(err)
+	}
+	return err
+}
+
+func (self *clientStream) RecvMsg(m interface{}) error {
+	err := self.ClientStream.RecvMsg(m)
+	if err != nil || !self.serverStreams {
+		// Without server streaming, the single response ends the call
+		self.finish(err)
+	}
+	return err
+}
@@ -0,0 +1,266 @@
+package rollbargrpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	rollbargrpc "github.com/comstud/go-rollbar/rollbar/grpc"
+	"github.com/comstud/go-rollbar/rollbar/rollbartest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Recorder that also records telemetry
+type telemetryRecorder struct {
+	*rollbartest.Recorder
+
+	mu      sync.Mutex
+	entries []*rollbar.NotifierTelemetry
+}
+
+func (self *telemetryRecorder) AddTelemetry(entry *rollbar.NotifierTelemetry) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.entries = append(self.entries, entry)
+}
+
+// grpc_code of each recorded call
+func (self *telemetryRecorder) codes() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	var got []string
+	for _, entry := range self.entries {
+		got = append(got, entry.Body["grpc_code"].(string))
+	}
+	return got
+}
+
+// Wait for 'n' calls to be recorded
+func (self *telemetryRecorder) waitCodes(t *testing.T, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := self.codes()
+		if len(got) >= n || time.Now().After(deadline) {
+			return got
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Echo panics when asked to, Collect is client streaming and Repeat is
+// server streaming, blocking until the call is done when asked to
+var testService = grpc.ServiceDesc{
+	ServiceName: "rollbargrpc.Test",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := &wrapperspb.StringValue{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				value := req.(*wrapperspb.StringValue).Value
+				if value == "panic" {
+					panic("secret panic value")
+				}
+				return wrapperspb.String(value), nil
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/rollbargrpc.Test/Echo"}
+			return interceptor(ctx, in, info, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Collect",
+			ClientStreams: true,
+			Handler: func(srv interface{}, ss grpc.ServerStream) error {
+				var values []string
+				for {
+					in := &wrapperspb.StringValue{}
+					if err := ss.RecvMsg(in); err == io.EOF {
+						break
+					} else if err != nil {
+						return err
+					}
+					values = append(values, in.Value)
+				}
+				return ss.SendMsg(wrapperspb.String(strings.Join(values, ",")))
+			},
+		},
+		{
+			StreamName:    "Repeat",
+			ServerStreams: true,
+			Handler: func(srv interface{}, ss grpc.ServerStream) error {
+				in := &wrapperspb.StringValue{}
+				if err := ss.RecvMsg(in); err != nil {
+					return err
+				}
+				if in.Value == "block" {
+					<-ss.Context().Done()
+					return ss.Context().Err()
+				}
+				for i := 0; i < 3; i++ {
+					if err := ss.SendMsg(in); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	},
+}
+
+func newTestConn(t *testing.T, client *telemetryRecorder) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(rollbargrpc.UnaryServerInterceptor(client, nil)),
+		grpc.StreamInterceptor(rollbargrpc.StreamServerInterceptor(client, nil)),
+	)
+	server.RegisterService(&testService, struct{}{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	cc, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(rollbargrpc.StreamClientInterceptor(client)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return cc
+}
+
+func TestStreamClientInterceptorRecordsOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream int
+		send   []string
+		// RecvMsg() calls made by the caller after closing
+		recvs int
+		want  string
+	}{
+		{"client streaming", 0, []string{"a", "b"}, 1, "OK"},
+		{"server streaming", 1, []string{"x"}, 4, "OK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &telemetryRecorder{Recorder: rollbartest.NewRecorder()}
+			cc := newTestConn(t, client)
+
+			desc := &testService.Streams[tt.stream]
+			cs, err := cc.NewStream(context.Background(), desc, "/rollbargrpc.Test/"+desc.StreamName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, value := range tt.send {
+				if err := cs.SendMsg(wrapperspb.String(value)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cs.CloseSend()
+			for i := 0; i < tt.recvs; i++ {
+				cs.RecvMsg(&wrapperspb.StringValue{})
+			}
+
+			// Recorded without the caller having to see io.EOF
+			client.waitCodes(t, 1)
+			time.Sleep(10 * time.Millisecond)
+			if got := client.codes(); len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Expected a single %s call, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptorRecordsCancel(t *testing.T) {
+	client := &telemetryRecorder{Recorder: rollbartest.NewRecorder()}
+	cc := newTestConn(t, client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	desc := &testService.Streams[1]
+	cs, err := cc.NewStream(ctx, desc, "/rollbargrpc.Test/Repeat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SendMsg(wrapperspb.String("block"))
+	cancel()
+
+	// The caller never calls RecvMsg()
+	if got := client.waitCodes(t, 1); len(got) != 1 || got[0] != codes.Canceled.String() {
+		t.Errorf("Expected a single Canceled call, got %v", got)
+	}
+}
+
+// ClientStream that has already finished
+type doneStream struct {
+	grpc.ClientStream
+}
+
+func (self *doneStream) RecvMsg(m interface{}) error {
+	return io.EOF
+}
+
+func TestStreamClientInterceptorContextAlreadyDone(t *testing.T) {
+	client := &telemetryRecorder{Recorder: rollbartest.NewRecorder()}
+	cc := newTestConn(t, client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A streamer that doesn't check ctx, so the stream's context watch
+	// fires as soon as it's set up
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &doneStream{}, nil
+	}
+	interceptor := rollbargrpc.StreamClientInterceptor(client)
+	cs, err := interceptor(ctx, &testService.Streams[1], cc, "/rollbargrpc.Test/Repeat", streamer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := client.waitCodes(t, 1); len(got) != 1 || got[0] != codes.Canceled.String() {
+		t.Errorf("Expected a single Canceled call, got %v", got)
+	}
+	cs.RecvMsg(&wrapperspb.StringValue{})
+	if got := client.codes(); len(got) != 1 {
+		t.Errorf("Expected the call to be recorded once, got %v", got)
+	}
+}
+
+func TestUnaryServerInterceptorHidesPanics(t *testing.T) {
+	client := &telemetryRecorder{Recorder: rollbartest.NewRecorder()}
+	cc := newTestConn(t, client)
+
+	err := cc.Invoke(context.Background(), "/rollbargrpc.Test/Echo", wrapperspb.String("panic"), &wrapperspb.StringValue{})
+	st := status.Convert(err)
+	if st.Code() != codes.Internal {
+		t.Fatalf("Expected Internal, got %v", err)
+	}
+	if strings.Contains(st.Message(), "secret") {
+		t.Errorf("Panic value leaked to the client: %q", st.Message())
+	}
+
+	found := client.WaitReported(t, &rollbartest.NotificationFilter{Level: rollbar.LV_CRITICAL}, 0)
+	if len(found) != 1 || found[0].GetTitle() != "secret panic value" {
+		t.Errorf("Expected the panic to be reported, got %v", found)
+	}
+}
@@ -0,0 +1,197 @@
+// Package rollbargrpc provides gRPC interceptors that report to rollbar.
+package rollbargrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"strings"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Status codes reported when ServerOptions.Codes is nil
+var DefaultReportCodes = []codes.Code{
+	codes.Unknown,
+	codes.Internal,
+	codes.DataLoss,
+	codes.Unimplemented,
+}
+
+// Options for the server interceptors
+type ServerOptions struct {
+	// Errors with these status codes are reported. Defaults to
+	// DefaultReportCodes
+	Codes []codes.Code
+
+	// Level used for recovered panics. Defaults to LV_CRITICAL
+	PanicLevel rollbar.NotificationLevel
+
+	// Level used for reported errors. Defaults to LV_ERROR
+	ErrorLevel rollbar.NotificationLevel
+
+	// Re-panic after reporting instead of returning an Internal error
+	Repanic bool
+}
+
+type serverReporter struct {
+	client  rollbar.Client
+	options ServerOptions
+}
+
+func newServerReporter(client rollbar.Client, options *ServerOptions) *serverReporter {
+	self := &serverReporter{client: client}
+	if options != nil {
+		self.options = *options
+	}
+	if self.options.Codes == nil {
+		self.options.Codes = DefaultReportCodes
+	}
+	if self.options.PanicLevel == "" {
+		self.options.PanicLevel = rollbar.LV_CRITICAL
+	}
+	if self.options.ErrorLevel == "" {
+		self.options.ErrorLevel = rollbar.LV_ERROR
+	}
+	return self
+}
+
+// Unary server interceptor that recovers panics and reports errors.
+// 'options' may be nil to use the defaults.
+func UnaryServerInterceptor(client rollbar.Client, options *ServerOptions) grpc.UnaryServerInterceptor {
+	reporter := newServerReporter(client, options)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		ctx = reporter.scope(ctx, info.FullMethod)
+		defer reporter.recover(ctx, &err)
+
+		resp, err = handler(ctx, req)
+		reporter.reportError(ctx, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// Stream server interceptor that recovers panics and reports errors.
+// 'options' may be nil to use the defaults.
+func StreamServerInterceptor(client rollbar.Client, options *ServerOptions) grpc.StreamServerInterceptor {
+	reporter := newServerReporter(client, options)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := reporter.scope(ss.Context(), info.FullMethod)
+		defer reporter.recover(ctx, &err)
+
+		err = handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		reporter.reportError(ctx, info.FullMethod, err)
+		return err
+	}
+}
+
+// ServerStream with a replaced context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (self *serverStream) Context() context.Context {
+	return self.ctx
+}
+
+// Install a scope holding the request info, so handlers can add to it
+func (self *serverReporter) scope(ctx context.Context, method string) context.Context {
+	ctx, scope := rollbar.WithScope(ctx)
+	scope.SetRequest(self.notifierRequest(ctx, method))
+	scope.SetContext(method)
+	return ctx
+}
+
+func (self *serverReporter) notifierRequest(ctx context.Context, method string) *rollbar.NotifierRequest {
+	notif_req := &rollbar.NotifierRequest{
+		URL:    method,
+		Method: method,
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		notif_req.Headers = make(map[string]string, len(md))
+		for k, v := range md {
+			notif_req.Headers[k] = strings.Join(v, ", ")
+		}
+		if authority := md.Get(":authority"); len(authority) != 0 {
+			notif_req.URL = "grpc://" + authority[0] + method
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		notif_req.UserIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(notif_req.UserIP); err == nil {
+			notif_req.UserIP = host
+		}
+	}
+
+	return notif_req.Scrub(self.client.Options().ScrubFields)
+}
+
+func (self *serverReporter) shouldReport(code codes.Code) bool {
+	for _, c := range self.options.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *serverReporter) recover(ctx context.Context, err *error) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+
+	pc := make([]uintptr, 100)
+	num := runtime.Callers(3, pc)
+
+	message := fmt.Sprint(rec)
+	notif := self.client.NewTraceNotificationWithContext(ctx, self.options.PanicLevel, message, nil)
+	if rec_err, ok := rec.(error); ok {
+		notif.Trace.AddExceptionFromError(rec_err)
+	} else {
+		notif.Trace.Exception = &rollbar.NotifierException{
+			Class:   "panic",
+			Message: message,
+		}
+	}
+	notif.Trace.AddRuntimeFrames(runtime.CallersFrames(pc[:num]))
+	self.send(notif)
+
+	if self.options.Repanic {
+		panic(rec)
+	}
+
+	// The panic value could be sensitive, so only rollbar gets it
+	*err = status.Error(codes.Internal, "Internal error")
+}
+
+func (self *serverReporter) reportError(ctx context.Context, method string, err error) {
+	if err == nil {
+		return
+	}
+
+	code := status.Code(err)
+	if !self.shouldReport(code) {
+		return
+	}
+
+	notif := self.client.NewMessageNotificationWithContext(
+		ctx,
+		self.options.ErrorLevel,
+		fmt.Sprintf("%s returned %s: %s", method, code, status.Convert(err).Message()),
+		rollbar.CustomInfo{"grpc_code": code.String()},
+	)
+	self.send(notif)
+}
+
+func (self *serverReporter) send(notif rollbar.Notification) {
+	rollbar.SendNotificationAsync(self.client, notif)
+}
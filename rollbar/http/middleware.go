@@ -0,0 +1,331 @@
+// Package rollbarhttp provides net/http integration for rollbar.
+package rollbarhttp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+const (
+	DEFAULT_MIN_STATUS  = 500
+	DEFAULT_UUID_HEADER = "X-Rollbar-UUID"
+)
+
+type uuidContextKey struct{}
+
+// Options for the middleware
+type Options struct {
+	// Responses with a status code at or above this are reported.
+	// Defaults to DEFAULT_MIN_STATUS. Use a negative value to only report
+	// panics.
+	MinStatus int
+
+	// Level used for recovered panics. Defaults to LV_CRITICAL
+	PanicLevel rollbar.NotificationLevel
+
+	// Level used for error responses. Defaults to LV_ERROR
+	StatusLevel rollbar.NotificationLevel
+
+	// Proxies (IPs or CIDRs) trusted to set X-Forwarded-For and
+	// X-Forwarded-Proto. With none, the remote address is always used.
+	TrustedProxies []string
+
+	// Response header that receives the occurrence UUID. Defaults to
+	// DEFAULT_UUID_HEADER. Use "-" to disable.
+	UUIDHeader string
+
+	// Maximum number of request body bytes captured, out of what the
+	// handler reads. Defaults to rollbar.DEFAULT_MAX_BODY_SIZE. Use a
+	// negative value to skip the body.
+	MaxBodySize int64
+
+	// Re-panic after reporting instead of responding with a 500
+	Repanic bool
+}
+
+// Middleware reporting panics and error responses to rollbar
+type Middleware struct {
+	client  rollbar.Client
+	options Options
+	proxies []*net.IPNet
+}
+
+// Create a new middleware. 'options' may be nil to use the defaults.
+func New(client rollbar.Client, options *Options) (*Middleware, error) {
+	self := &Middleware{client: client}
+	if options != nil {
+		self.options = *options
+	}
+
+	if self.options.MinStatus == 0 {
+		self.options.MinStatus = DEFAULT_MIN_STATUS
+	}
+	if self.options.PanicLevel == "" {
+		self.options.PanicLevel = rollbar.LV_CRITICAL
+	}
+	if self.options.StatusLevel == "" {
+		self.options.StatusLevel = rollbar.LV_ERROR
+	}
+	if self.options.UUIDHeader == "" {
+		self.options.UUIDHeader = DEFAULT_UUID_HEADER
+	}
+
+	proxies, err := rollbar.ParseTrustedProxies(self.options.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	self.proxies = proxies
+
+	return self, nil
+}
+
+// Get the occurrence UUID assigned to a request. This is the UUID used if
+// the request ends up being reported.
+func UUIDFromContext(ctx context.Context) string {
+	uuid, _ := ctx.Value(uuidContextKey{}).(string)
+	return uuid
+}
+
+// Wrap a handler
+func (self *Middleware) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		uuid := rollbar.NewUUID()
+		req = req.WithContext(context.WithValue(req.Context(), uuidContextKey{}, uuid))
+
+		// Keep what the handler reads of the body, as it can't be read
+		// again once the request is reported
+		var body *bodyCapture
+		max_size := self.options.MaxBodySize
+		if max_size == 0 {
+			max_size = rollbar.DEFAULT_MAX_BODY_SIZE
+		}
+		if max_size > 0 && req.Body != nil && req.Body != http.NoBody {
+			body = &bodyCapture{ReadCloser: req.Body, max: max_size}
+			req.Body = body
+		}
+
+		// Handlers can add to the scope (e.g. the person) and it'll be
+		// included if the request is reported. The request itself is only
+		// built if something is reported.
+		ctx, scope := rollbar.WithScope(req.Context())
+		req = req.WithContext(ctx)
+		scope.SetRequestFunc(func() *rollbar.NotifierRequest {
+			return self.notifierRequest(req, body)
+		})
+
+		rw := &responseWriter{
+			ResponseWriter: w,
+			middleware:     self,
+			uuid:           uuid,
+		}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			pc := make([]uintptr, 100)
+			num := runtime.Callers(3, pc)
+
+			self.reportPanic(req, uuid, rec, runtime.CallersFrames(pc[:num]))
+
+			if self.options.Repanic {
+				panic(rec)
+			}
+
+			if !rw.wroteHeader && !rw.hijacked {
+				rw.panicked = true
+				rw.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+
+		handler.ServeHTTP(rw, req)
+
+		if rw.reportable() {
+			self.reportStatus(req, uuid, rw.status)
+		}
+	})
+}
+
+// Wrap a handler function
+func (self *Middleware) HandlerFunc(fn http.HandlerFunc) http.Handler {
+	return self.Handler(fn)
+}
+
+func (self *Middleware) reportPanic(req *http.Request, uuid string, rec interface{}, frames *runtime.Frames) {
+	message := fmt.Sprint(rec)
+	notif := self.client.NewTraceNotificationWithContext(req.Context(), self.options.PanicLevel, message, nil)
+
+	err, ok := rec.(error)
+	if ok {
+		notif.Trace.AddExceptionFromError(err)
+	} else {
+		notif.Trace.Exception = &rollbar.NotifierException{
+			Class:   "panic",
+			Message: message,
+		}
+	}
+	notif.Trace.AddRuntimeFrames(frames)
+
+	self.send(req, uuid, notif)
+}
+
+func (self *Middleware) reportStatus(req *http.Request, uuid string, status int) {
+	message := fmt.Sprintf("%s %s returned %d %s", req.Method, req.URL.Path, status, http.StatusText(status))
+	notif := self.client.NewMessageNotificationWithContext(req.Context(), self.options.StatusLevel, message, nil)
+	self.send(req, uuid, notif)
+}
+
+func (self *Middleware) notifierRequest(req *http.Request, body *bodyCapture) *rollbar.NotifierRequest {
+	scrub_fields := self.client.Options().ScrubFields
+	// The body was already captured, so nothing is read here
+	notif_req, _ := rollbar.NewNotifierRequest(
+		req,
+		&rollbar.NotifierRequestOptions{
+			MaxBodySize:    -1,
+			TrustedProxies: self.proxies,
+			ScrubFields:    scrub_fields,
+		},
+	)
+	if body != nil {
+		data, truncated := body.captured(req.ContentLength)
+		notif_req.SetBody(req.Header.Get("Content-Type"), data, truncated, scrub_fields)
+	}
+	return notif_req
+}
+
+func (self *Middleware) send(req *http.Request, uuid string, notif rollbar.Notification) {
+	notif.SetUUID(uuid)
+	if notif.GetContext() == "" {
+		notif.SetContext(req.URL.Path)
+	}
+
+	rollbar.SendNotificationAsync(self.client, notif)
+}
+
+// Request body wrapper keeping a copy of what the handler reads, up to one
+// byte past the maximum so that truncation shows
+type bodyCapture struct {
+	io.ReadCloser
+	max int64
+
+	lock sync.Mutex
+	data []byte
+	eof  bool
+}
+
+func (self *bodyCapture) Read(p []byte) (int, error) {
+	n, err := self.ReadCloser.Read(p)
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if room := self.max + 1 - int64(len(self.data)); room > 0 {
+		self.data = append(self.data, p[:min(int64(n), room)]...)
+	}
+	if err == io.EOF {
+		self.eof = true
+	}
+	return n, err
+}
+
+// Get the captured data and whether it's only the start of the body, as
+// the handler stopped reading early or the body was too large
+func (self *bodyCapture) captured(content_length int64) ([]byte, bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if int64(len(self.data)) > self.max {
+		return self.data[:self.max], true
+	}
+	complete := self.eof || (content_length >= 0 && int64(len(self.data)) >= content_length)
+	return self.data, !complete
+}
+
+// ResponseWriter wrapper that records the status and adds the UUID header
+// to reportable responses
+type responseWriter struct {
+	http.ResponseWriter
+	middleware  *Middleware
+	uuid        string
+	status      int
+	wroteHeader bool
+	panicked    bool
+	hijacked    bool
+}
+
+func (self *responseWriter) reportable() bool {
+	min_status := self.middleware.options.MinStatus
+	return self.wroteHeader && min_status > 0 && self.status >= min_status
+}
+
+func (self *responseWriter) WriteHeader(status int) {
+	if self.wroteHeader {
+		return
+	}
+	// Informational statuses (e.g. 103 Early Hints) come before the real
+	// one. 101 Switching Protocols is final, as net/http treats it.
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		self.ResponseWriter.WriteHeader(status)
+		return
+	}
+	self.status = status
+	self.wroteHeader = true
+	if self.middleware.options.UUIDHeader != "-" && (self.reportable() || self.panicked) {
+		self.Header().Set(self.middleware.options.UUIDHeader, self.uuid)
+	}
+	self.ResponseWriter.WriteHeader(status)
+}
+
+func (self *responseWriter) Write(b []byte) (int, error) {
+	if !self.wroteHeader {
+		self.WriteHeader(http.StatusOK)
+	}
+	return self.ResponseWriter.Write(b)
+}
+
+func (self *responseWriter) Flush() {
+	if !self.wroteHeader {
+		self.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := self.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Take over the connection (e.g. for websockets), if the underlying
+// ResponseWriter allows it. The response is then left to the handler,
+// even if it panics.
+func (self *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := self.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err == nil {
+		self.hijacked = true
+	}
+	return conn, buf, err
+}
+
+// HTTP/2 server push, if the underlying ResponseWriter supports it
+func (self *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := self.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// For use by http.ResponseController
+func (self *responseWriter) Unwrap() http.ResponseWriter {
+	return self.ResponseWriter
+}
@@ -0,0 +1,293 @@
+package rollbarhttp_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	rollbarhttp "github.com/comstud/go-rollbar/rollbar/http"
+	"github.com/comstud/go-rollbar/rollbar/rollbartest"
+)
+
+func newTestMiddleware(t *testing.T) (*rollbartest.Recorder, *rollbarhttp.Middleware) {
+	t.Helper()
+	recorder := rollbartest.NewRecorder()
+	middleware, err := rollbarhttp.New(recorder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return recorder, middleware
+}
+
+func TestMiddlewareReports(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    *rollbarhttp.Options
+		handler    func(w http.ResponseWriter, req *http.Request)
+		wantStatus int
+		wantLevel  rollbar.NotificationLevel
+		wantTitle  string
+	}{
+		{
+			"ok",
+			nil,
+			func(w http.ResponseWriter, req *http.Request) { w.Write([]byte("ok")) },
+			http.StatusOK, "", "",
+		},
+		{
+			"client error",
+			nil,
+			func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			http.StatusNotFound, "", "",
+		},
+		{
+			"server error",
+			nil,
+			func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+			http.StatusBadGateway, rollbar.LV_ERROR, "GET /path returned 502 Bad Gateway",
+		},
+		{
+			"min status",
+			&rollbarhttp.Options{MinStatus: 400},
+			func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			http.StatusNotFound, rollbar.LV_ERROR, "GET /path returned 404 Not Found",
+		},
+		{
+			"panics only",
+			&rollbarhttp.Options{MinStatus: -1},
+			func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			http.StatusInternalServerError, "", "",
+		},
+		{
+			"panic",
+			nil,
+			func(w http.ResponseWriter, req *http.Request) { panic("boom") },
+			http.StatusInternalServerError, rollbar.LV_CRITICAL, "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := rollbartest.NewRecorder()
+			middleware, err := rollbarhttp.New(recorder, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var uuid string
+			handler := middleware.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				uuid = rollbarhttp.UUIDFromContext(req.Context())
+				tt.handler(w, req)
+			})
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/path", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			notifs := recorder.Notifications()
+			if tt.wantLevel == "" {
+				if len(notifs) != 0 || w.Header().Get(rollbarhttp.DEFAULT_UUID_HEADER) != "" {
+					t.Errorf("Expected nothing reported, got %d notifications", len(notifs))
+				}
+				return
+			}
+
+			if len(notifs) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(notifs))
+			}
+			notif := notifs[0]
+			if notif.GetLevel() != tt.wantLevel || notif.GetTitle() != tt.wantTitle {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantLevel, tt.wantTitle, notif.GetLevel(), notif.GetTitle())
+			}
+			if notif.GetUUID() != uuid || w.Header().Get(rollbarhttp.DEFAULT_UUID_HEADER) != uuid {
+				t.Errorf("Expected UUID %s in the notification and header", uuid)
+			}
+			if req := notif.GetRequest(); req == nil || req.Method != "GET" || notif.GetContext() != "/path" {
+				t.Errorf("Expected the request to be attached, got %+v", req)
+			}
+		})
+	}
+}
+
+func TestMiddlewareInformationalStatus(t *testing.T) {
+	recorder, middleware := newTestMiddleware(t)
+	// httptest.ResponseRecorder takes a 1xx as the final status, so this
+	// needs a real server
+	server := httptest.NewServer(middleware.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get(rollbarhttp.DEFAULT_UUID_HEADER) == "" {
+		t.Errorf("Expected the UUID header")
+	}
+	if notifs := recorder.Notifications(); len(notifs) != 1 || notifs[0].GetLevel() != rollbar.LV_ERROR {
+		t.Errorf("Expected the 500 reported, got %d notifications", len(notifs))
+	}
+}
+
+func TestMiddlewareBody(t *testing.T) {
+	const body = "a=1&password=hunter2"
+
+	tests := []struct {
+		name     string
+		options  *rollbarhttp.Options
+		read     int
+		wantPOST map[string]interface{}
+	}{
+		{"read", nil, -1, map[string]interface{}{"a": "1", "password": rollbar.SCRUBBED_VALUE}},
+		{"partly read", nil, 5, nil},
+		{"not read", nil, 0, nil},
+		{"too large", &rollbarhttp.Options{MaxBodySize: 5}, -1, nil},
+		{"skipped", &rollbarhttp.Options{MaxBodySize: -1}, -1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := rollbartest.NewRecorder()
+			middleware, err := rollbarhttp.New(recorder, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			handler := middleware.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				var data []byte
+				if tt.read < 0 {
+					data, _ = io.ReadAll(req.Body)
+				} else {
+					data = make([]byte, tt.read)
+					io.ReadFull(req.Body, data)
+				}
+				got = string(data)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			req := httptest.NewRequest("POST", "/path", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.read < 0 && got != body {
+				t.Errorf("Expected the handler to read the whole body, got %q", got)
+			}
+
+			notifs := recorder.Notifications()
+			if len(notifs) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(notifs))
+			}
+			notif_req := notifs[0].GetRequest()
+			if notif_req == nil {
+				t.Fatalf("Expected the request to be attached")
+			}
+			if len(notif_req.POSTParams) != len(tt.wantPOST) {
+				t.Errorf("Expected POST params %v, got %v", tt.wantPOST, notif_req.POSTParams)
+			}
+			for k, v := range tt.wantPOST {
+				if notif_req.POSTParams[k] != v {
+					t.Errorf("Expected POST param %s=%v, got %v", k, v, notif_req.POSTParams[k])
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareHijack(t *testing.T) {
+	tests := []struct {
+		name      string
+		panic     bool
+		wantNotif int
+	}{
+		{"hijacked", false, 0},
+		{"panic after hijacking", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, middleware := newTestMiddleware(t)
+			server := httptest.NewServer(middleware.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				// As websocket libraries do
+				hijacker, ok := w.(http.Hijacker)
+				if !ok {
+					t.Errorf("ResponseWriter isn't an http.Hijacker")
+					return
+				}
+				conn, buf, err := hijacker.Hijack()
+				if err != nil {
+					t.Errorf("Error hijacking: %s", err)
+					return
+				}
+				buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nhi")
+				buf.Flush()
+				conn.Close()
+				if tt.panic {
+					panic("after hijack")
+				}
+			}))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Error requesting: %s", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || string(body) != "hi" {
+				t.Errorf("Expected the hijacked response, got %d %q", resp.StatusCode, body)
+			}
+
+			if n := len(recorder.Notifications()); n != tt.wantNotif {
+				t.Errorf("Expected %d notifications, got %d", tt.wantNotif, n)
+			}
+		})
+	}
+}
+
+// ResponseWriter that supports server push
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (self *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	self.pushed = append(self.pushed, target)
+	return nil
+}
+
+func TestMiddlewarePassThrough(t *testing.T) {
+	_, middleware := newTestMiddleware(t)
+
+	var push_err, hijack_err error
+	handler := middleware.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		push_err = w.(http.Pusher).Push("/style.css", nil)
+		_, _, hijack_err = w.(http.Hijacker).Hijack()
+	})
+
+	pusher := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(pusher, httptest.NewRequest("GET", "/", nil))
+	if push_err != nil || len(pusher.pushed) != 1 || pusher.pushed[0] != "/style.css" {
+		t.Errorf("Expected the push to pass through, got %v (%v)", pusher.pushed, push_err)
+	}
+	if !errors.Is(hijack_err, http.ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported hijacking a recorder, got %v", hijack_err)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !errors.Is(push_err, http.ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported pushing to a recorder, got %v", push_err)
+	}
+}
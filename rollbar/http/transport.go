@@ -0,0 +1,110 @@
+package rollbarhttp
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Options for the transport
+type TransportOptions struct {
+	// Send a notification when the underlying transport returns an error
+	ReportErrors bool
+
+	// Send a notification for responses with these status codes
+	ReportCodes []int
+
+	// Level used for notifications. Defaults to LV_WARNING
+	Level rollbar.NotificationLevel
+}
+
+// http.RoundTripper that records outgoing requests as telemetry and
+// optionally reports failures
+type Transport struct {
+	client  rollbar.Client
+	base    http.RoundTripper
+	options TransportOptions
+}
+
+// Create a new transport wrapping 'base'. If 'base' is nil,
+// http.DefaultTransport is used. 'options' may be nil to only record
+// telemetry.
+func NewTransport(client rollbar.Client, base http.RoundTripper, options *TransportOptions) *Transport {
+	self := &Transport{
+		client: client,
+		base:   base,
+	}
+	if self.base == nil {
+		self.base = http.DefaultTransport
+	}
+	if options != nil {
+		self.options = *options
+	}
+	if self.options.Level == "" {
+		self.options.Level = rollbar.LV_WARNING
+	}
+	return self
+}
+
+func (self *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := self.base.RoundTrip(req)
+	end := time.Now()
+
+	url := rollbar.ScrubURL(req.URL.String(), self.client.Options().ScrubFields)
+
+	status_code := 0
+	if resp != nil {
+		status_code = resp.StatusCode
+	}
+
+	level := rollbar.LV_INFO
+	if err != nil || status_code >= 500 {
+		level = rollbar.LV_ERROR
+	} else if status_code >= 400 {
+		level = rollbar.LV_WARNING
+	}
+
+	entry := rollbar.NewNetworkTelemetry(level, "http", req.Method, url, status_code, start, end)
+	entry.Body["host"] = req.URL.Host
+	entry.Body["duration_ms"] = end.Sub(start).Milliseconds()
+	if err != nil {
+		entry.Body["error"] = err.Error()
+	}
+	self.client.AddTelemetry(entry)
+
+	if err != nil && self.options.ReportErrors {
+		self.report(req, url, fmt.Sprintf("%s %s failed: %s", req.Method, url, err), status_code, end.Sub(start))
+	} else if err == nil && self.shouldReport(status_code) {
+		self.report(req, url, fmt.Sprintf("%s %s returned %d", req.Method, url, status_code), status_code, end.Sub(start))
+	}
+
+	return resp, err
+}
+
+func (self *Transport) shouldReport(status_code int) bool {
+	for _, code := range self.options.ReportCodes {
+		if code == status_code {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *Transport) report(req *http.Request, url string, message string, status_code int, duration time.Duration) {
+	custom := rollbar.CustomInfo{
+		"method":      req.Method,
+		"url":         url,
+		"host":        req.URL.Host,
+		"duration_ms": duration.Milliseconds(),
+	}
+	if status_code != 0 {
+		custom["status_code"] = status_code
+	}
+
+	notif := self.client.NewMessageNotificationWithContext(req.Context(), self.options.Level, message, custom)
+
+	rollbar.SendNotificationAsync(self.client, notif)
+}
@@ -0,0 +1,123 @@
+package rollbarhttp_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	rollbarhttp "github.com/comstud/go-rollbar/rollbar/http"
+	"github.com/comstud/go-rollbar/rollbar/rollbartest"
+)
+
+// Recorder that also keeps the telemetry added to it
+type telemetryRecorder struct {
+	*rollbartest.Recorder
+
+	mu        sync.Mutex
+	telemetry []*rollbar.NotifierTelemetry
+}
+
+func (self *telemetryRecorder) AddTelemetry(entry *rollbar.NotifierTelemetry) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.telemetry = append(self.telemetry, entry)
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (self roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return self(req)
+}
+
+func TestTransport(t *testing.T) {
+	refused := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		options    *rollbarhttp.TransportOptions
+		status     int
+		err        error
+		wantLevel  rollbar.NotificationLevel
+		wantReport string
+	}{
+		{"ok", nil, http.StatusOK, nil, rollbar.LV_INFO, ""},
+		{"client error", nil, http.StatusNotFound, nil, rollbar.LV_WARNING, ""},
+		{"server error", nil, http.StatusServiceUnavailable, nil, rollbar.LV_ERROR, ""},
+		{"error not reported", nil, 0, refused, rollbar.LV_ERROR, ""},
+		{
+			"error reported",
+			&rollbarhttp.TransportOptions{ReportErrors: true},
+			0, refused,
+			rollbar.LV_ERROR,
+			"GET http://api.example.com/users?token=%2A%2A%2A%2A%2A%2A%2A%2A failed: connection refused",
+		},
+		{
+			"code reported",
+			&rollbarhttp.TransportOptions{ReportCodes: []int{http.StatusServiceUnavailable}},
+			http.StatusServiceUnavailable, nil,
+			rollbar.LV_ERROR,
+			"GET http://api.example.com/users?token=%2A%2A%2A%2A%2A%2A%2A%2A returned 503",
+		},
+		{
+			"code not configured",
+			&rollbarhttp.TransportOptions{ReportCodes: []int{http.StatusServiceUnavailable}},
+			http.StatusBadGateway, nil,
+			rollbar.LV_ERROR, "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &telemetryRecorder{Recorder: rollbartest.NewRecorder()}
+			base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &http.Response{StatusCode: tt.status, Body: http.NoBody, Request: req}, nil
+			})
+			client := &http.Client{Transport: rollbarhttp.NewTransport(recorder, base, tt.options)}
+
+			resp, err := client.Get("http://api.example.com/users?token=secret")
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if resp != nil && resp.StatusCode != tt.status {
+				t.Errorf("Expected the response to pass through, got %d", resp.StatusCode)
+			}
+
+			if len(recorder.telemetry) != 1 {
+				t.Fatalf("Expected 1 telemetry entry, got %d", len(recorder.telemetry))
+			}
+			entry := recorder.telemetry[0]
+			if entry.Type != rollbar.TELEMETRY_NETWORK || entry.Level != tt.wantLevel {
+				t.Errorf("Expected %s network telemetry, got %s %s", tt.wantLevel, entry.Level, entry.Type)
+			}
+			if url, _ := entry.Body["url"].(string); strings.Contains(url, "secret") {
+				t.Errorf("Expected the URL to be scrubbed, got %s", url)
+			}
+			if entry.Body["host"] != "api.example.com" || entry.Body["status_code"] != tt.status {
+				t.Errorf("Expected host and status in the telemetry, got %v", entry.Body)
+			}
+			if _, has_err := entry.Body["error"]; has_err != (tt.err != nil) {
+				t.Errorf("Expected error in the telemetry only on failure, got %v", entry.Body)
+			}
+
+			notifs := recorder.Notifications()
+			if tt.wantReport == "" {
+				if len(notifs) != 0 {
+					t.Errorf("Expected nothing reported, got %d notifications", len(notifs))
+				}
+				return
+			}
+			if len(notifs) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(notifs))
+			}
+			if notif := notifs[0]; notif.GetLevel() != rollbar.LV_WARNING || notif.GetTitle() != tt.wantReport {
+				t.Errorf("Expected warning %q, got %s %q", tt.wantReport, notif.GetLevel(), notif.GetTitle())
+			}
+		})
+	}
+}
@@ -3,30 +3,31 @@ package rollbar
 import (
 	"errors"
 	"fmt"
+	"net/url"
 )
 
 // Item struct
 type Item struct {
-	ID                      uint64   `json:"id"`
-	Project_id              uint64   `json:"project_id"`
-	Counter                 uint64   `json:"counter"`
-	Environment             string   `json:"environment"`
-	Platform                string   `json:"platform"`
-	Framework               string   `json:"framework"`
-	Hash                    string   `json:"hash"`
-	Title                   string   `json:"title"`
-	FirstOccurrenceId       uint64   `json:"first_occurrence_id"`
-	FirstOccurenceTimestamp JSONTime `json:"first_occurrence_timestamp"`
-	ActivatingOccurrenceId  uint64   `json:"activating_occurrence_id"`
-	LastActivatedTimestamp  JSONTime `json:"last_activated_timestamp"`
-	LastResolvedTimestamp   JSONTime `json:"last_resolved_timestamp"`
-	LastMutedTimestamp      JSONTime `json:"last_muted_timestamp"`
-	LastOccurrenceId        uint64   `json:"last_occurrence_id"`
-	LastOccurenceTimestamp  JSONTime `json:"last_occurrence_timestamp"`
-	TotalOccurrences        uint64   `json:"total_occurrences"`
-	LastModifiedBy          uint64   `json:"last_modified_by"`
-	Status                  string   `json:"status"`
-	Level                   string   `json:"level"`
+	ID                      uint64     `json:"id"`
+	Project_id              uint64     `json:"project_id"`
+	Counter                 uint64     `json:"counter"`
+	Environment             string     `json:"environment"`
+	Platform                string     `json:"platform"`
+	Framework               string     `json:"framework"`
+	Hash                    string     `json:"hash"`
+	Title                   string     `json:"title"`
+	FirstOccurrenceId       uint64     `json:"first_occurrence_id"`
+	FirstOccurenceTimestamp JSONTime   `json:"first_occurrence_timestamp"`
+	ActivatingOccurrenceId  uint64     `json:"activating_occurrence_id"`
+	LastActivatedTimestamp  JSONTime   `json:"last_activated_timestamp"`
+	LastResolvedTimestamp   JSONTime   `json:"last_resolved_timestamp"`
+	LastMutedTimestamp      JSONTime   `json:"last_muted_timestamp"`
+	LastOccurrenceId        uint64     `json:"last_occurrence_id"`
+	LastOccurenceTimestamp  JSONTime   `json:"last_occurrence_timestamp"`
+	TotalOccurrences        uint64     `json:"total_occurrences"`
+	LastModifiedBy          uint64     `json:"last_modified_by"`
+	Status                  ItemStatus `json:"status"`
+	Level                   string     `json:"level"`
 	// No idea what this is yet
 	IntegrationsData interface{} `json:"integrations_data"`
 }
@@ -57,6 +58,7 @@ func (self *client) GetItem(id uint64) (*ItemResponse, error) {
 	item_resp := &ItemResponse{}
 
 	err := self.httpGet(
+		scopeRead,
 		fmt.Sprintf("/item/%d", id),
 		nil,
 		&item_resp,
@@ -74,6 +76,7 @@ func (self *client) GetItemByCounter(counter uint64) (*ItemResponse, error) {
 	item_resp := &ItemResponse{}
 
 	err := self.httpGet(
+		scopeRead,
 		fmt.Sprintf("/item_by_counter/%d", counter),
 		nil,
 		&item_resp,
@@ -87,39 +90,259 @@ func (self *client) GetItemByCounter(counter uint64) (*ItemResponse, error) {
 }
 
 // Update an item's status by its id
-func (self *client) SetItemStatus(id uint64, status string) error {
-	item_update := map[string]interface{}{
-		"status": status,
+func (self *client) SetItemStatus(id uint64, status ItemStatus) error {
+	_, err := self.UpdateItem(id, &ItemUpdate{Status: status})
+	return err
+}
+
+// Update an item's status by its counter
+func (self *client) SetItemStatusByCounter(counter uint64, status ItemStatus) error {
+	_, err := self.UpdateItemByCounter(counter, &ItemUpdate{Status: status})
+	return err
+}
+
+// Update an item by its id, returning the updated item
+func (self *client) UpdateItem(id uint64, update *ItemUpdate) (*ItemResponse, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
 	}
 
-	update_resp := &BaseAPIResponse{}
+	item_resp := &ItemResponse{}
 
 	err := self.httpPatch(
+		scopeWrite,
 		fmt.Sprintf("/item/%d", id),
-		&item_update,
-		&update_resp,
+		update,
+		&item_resp,
 	)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if update_resp.Err != 0 {
-		return errors.New(update_resp.Message)
+	return item_resp, nil
+}
+
+// Update an item by its counter, returning the updated item
+func (self *client) UpdateItemByCounter(counter uint64, update *ItemUpdate) (*ItemResponse, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	item_resp, err := self.GetItemByCounter(counter)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return self.UpdateItem(item_resp.ID, update)
 }
 
-// Update an item's status by its counter
-func (self *client) SetItemStatusByCounter(counter uint64, status string) error {
-	item_resp, err := self.GetItemByCounter(counter)
-	if err != nil || item_resp.Err != 0 {
-		if err == nil {
-			err = errors.New(item_resp.Message)
+// Filters for listing items. Empty fields are not filtered on.
+type ItemFilter struct {
+	Status ItemStatus
+
+	Levels       []NotificationLevel
+	Environments []string
+
+	// Username of the assigned user, or "unassigned"
+	AssignedUser string
+
+	// Search text, as used in the rollbar UI's search box
+	Query string
+}
+
+func (self *ItemFilter) query() url.Values {
+	query := url.Values{}
+	if self == nil {
+		return query
+	}
+	if self.Status != "" {
+		query.Set("status", string(self.Status))
+	}
+	for _, level := range self.Levels {
+		query.Add("level", string(level))
+	}
+	for _, env := range self.Environments {
+		query.Add("environment", env)
+	}
+	if self.AssignedUser != "" {
+		query.Set("assigned_user", self.AssignedUser)
+	}
+	if self.Query != "" {
+		query.Set("query", self.Query)
+	}
+	return query
+}
+
+// Container for multiple items
+type ItemsResult struct {
+	Items      []*Item `json:"items"`
+	Page       uint64  `json:"page"`
+	TotalCount uint64  `json:"total_count"`
+}
+
+// Full API response for multiple items
+type ItemsResponse struct {
+	rollbar  *client
+	filter   *ItemFilter
+	pageSize int
+	BaseAPIResponse
+	Page         uint64
+	*ItemsResult `json:"result"`
+}
+
+// String representation of items response (pretty json)
+func (self *ItemsResponse) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Items response as a json string
+func (self *ItemsResponse) AsJSON() string {
+	if self.ItemsResult == nil {
+		return "[]"
+	}
+	return asJSON(self.Items)
+}
+
+// Items response as a pretty json string
+func (self *ItemsResponse) AsPrettyJSON() string {
+	if self.ItemsResult == nil {
+		return "[]"
+	}
+	return asPrettyJSON(self.Items)
+}
+
+// Does an items response have more pages? Uses the total count when the
+// API gives one, otherwise a page smaller than a full one is the last.
+func (self *ItemsResponse) HasMorePages() bool {
+	if !self.IsSuccess() || self.ItemsResult == nil {
+		return false
+	}
+	page_size := self.pageSize
+	more := pageHasMore(len(self.Items), &page_size)
+	if self.TotalCount > 0 && self.Page > 0 {
+		more = len(self.Items) > 0 &&
+			(self.Page-1)*uint64(page_size)+uint64(len(self.Items)) < self.TotalCount
+	}
+	return more
+}
+
+// Get the next page of items
+func (self *ItemsResponse) GetNextPage() (*ItemsResponse, error) {
+	if !self.HasMorePages() {
+		return self, nil
+	}
+	if self.rollbar == nil {
+		return nil, errNoPagerClient
+	}
+	resp := &ItemsResponse{
+		rollbar:  self.rollbar,
+		filter:   self.filter,
+		pageSize: max(self.pageSize, len(self.Items)),
+		Page:     self.Page + 1,
+	}
+	return self.rollbar.listItems(resp)
+}
+
+func (self *client) listItems(resp *ItemsResponse) (*ItemsResponse, error) {
+	query := resp.filter.query()
+	query.Set("page", fmt.Sprintf("%d", resp.Page))
+
+	err := self.httpGet(scopeRead, "/items", query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get first page of items matching 'filter' (which may be nil)
+func (self *client) ListItems(filter *ItemFilter) (*ItemsResponse, error) {
+	return self.ListItemsWithPage(filter, 1)
+}
+
+// Get a specific page of items matching 'filter' (which may be nil)
+func (self *client) ListItemsWithPage(filter *ItemFilter, page uint64) (*ItemsResponse, error) {
+	if page == 0 {
+		return nil, errors.New("Page must be greater than 0")
+	}
+	resp := &ItemsResponse{
+		rollbar:  self,
+		filter:   filter,
+		pageSize: ITEMS_PAGE_SIZE,
+		Page:     page,
+	}
+	return self.listItems(resp)
+}
+
+// Iterator over all items matching a filter. Pages are fetched as needed.
+//
+//	iter := client.IterItems(filter)
+//	for iter.Next() {
+//		item := iter.Item()
+//		fmt.Println(item.Counter, item.Title)
+//	}
+//	if err := iter.Err(); err != nil {
+//		return err
+//	}
+//
+// Deprecated: Use ItemsPager, which this is built on.
+type ItemIterator struct {
+	pager *Pager[*Item]
+	page  uint64
+	items []*Item
+	more  bool
+	item  *Item
+	err   error
+	done  bool
+}
+
+// Advance to the next item. Returns false when there are no more items or
+// an error occurred.
+func (self *ItemIterator) Next() bool {
+	if self.done {
+		return false
+	}
+
+	for len(self.items) == 0 {
+		if self.page != 0 && !self.more {
+			self.done = true
+			return false
+		}
+
+		self.page++
+		items, more, err := self.pager.fetch(self.page)
+		if err != nil {
+			self.err = err
+			self.done = true
+			return false
 		}
-		return fmt.Errorf("Error getting item ID: %s", err.Error())
+		if len(items) == 0 {
+			self.done = true
+			return false
+		}
+		self.items = items
+		self.more = more
 	}
 
-	return self.SetItemStatus(item_resp.ID, status)
+	self.item = self.items[0]
+	self.items = self.items[1:]
+	return true
+}
+
+// Get the current item
+func (self *ItemIterator) Item() *Item {
+	return self.item
+}
+
+// Get the error that stopped iteration, if any
+func (self *ItemIterator) Err() error {
+	return self.err
+}
+
+// Iterate over all items matching 'filter' (which may be nil)
+//
+// Deprecated: Use ItemsPager.
+func (self *client) IterItems(filter *ItemFilter) *ItemIterator {
+	return &ItemIterator{pager: self.ItemsPager(filter, nil)}
 }
@@ -0,0 +1,64 @@
+package rollbar
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestListItemsFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *ItemFilter
+		want   string
+	}{
+		{"nil", nil, "page=1"},
+		{"empty", &ItemFilter{}, "page=1"},
+		{
+			"all",
+			&ItemFilter{
+				Status:       ITEM_ACTIVE,
+				Levels:       []NotificationLevel{LV_ERROR, LV_CRITICAL},
+				Environments: []string{"production", "staging"},
+				AssignedUser: "unassigned",
+				Query:        "timeout db",
+			},
+			"assigned_user=unassigned&environment=production&environment=staging&level=error&level=critical&page=1&query=timeout+db&status=active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query []string
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				query = append(query, req.URL.RawQuery)
+
+				// Three items over two pages
+				if len(query) == 1 {
+					w.Write([]byte(`{"err": 0, "result": {"items": [{"id": 1}, {"id": 2}], "page": 1, "total_count": 3}}`))
+				} else {
+					w.Write([]byte(`{"err": 0, "result": {"items": [{"id": 3}], "page": 2, "total_count": 3}}`))
+				}
+			})
+
+			resp, err := c.ListItems(tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if query[0] != tt.want {
+				t.Errorf("Expected query %s, got %s", tt.want, query[0])
+			}
+
+			if _, err := resp.GetNextPage(); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(query) != 2 || query[1] == query[0] {
+				t.Fatalf("Expected a second page to be fetched, got %v", query)
+			}
+			want := tt.filter.query()
+			want.Set("page", "2")
+			if query[1] != want.Encode() {
+				t.Errorf("Expected the filter to be kept on the next page, got %s", query[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Status of an item
+type ItemStatus string
+
+const (
+	ITEM_ACTIVE   ItemStatus = ItemStatus("active")
+	ITEM_RESOLVED ItemStatus = ItemStatus("resolved")
+	ITEM_MUTED    ItemStatus = ItemStatus("muted")
+	ITEM_ARCHIVED ItemStatus = ItemStatus("archived")
+)
+
+// Is the status one rollbar accepts?
+func (self ItemStatus) IsValid() bool {
+	switch self {
+	case ITEM_ACTIVE, ITEM_RESOLVED, ITEM_MUTED, ITEM_ARCHIVED:
+		return true
+	}
+	return false
+}
+
+// Convert a string to an ItemStatus, validating it
+func ParseItemStatus(status string) (ItemStatus, error) {
+	item_status := ItemStatus(status)
+	if !item_status.IsValid() {
+		return "", fmt.Errorf("Invalid item status: %s", status)
+	}
+	return item_status, nil
+}
+
+// Snooze settings for ItemUpdate
+type ItemSnooze struct {
+	Enabled bool
+
+	// How long to snooze for. Only used when Enabled.
+	Expiration time.Duration
+}
+
+// Changes to make to an item. Only fields that are set are changed.
+type ItemUpdate struct {
+	Status ItemStatus
+	Level  NotificationLevel
+	Title  string
+
+	// Assign to a user by id
+	AssignedUserID uint64
+
+	// Remove the assigned user. Can't be used with AssignedUserID.
+	Unassign bool
+
+	// Version the item was resolved in. Usually used with ITEM_RESOLVED.
+	ResolvedInVersion string
+
+	Snooze *ItemSnooze
+}
+
+// Check that an update is valid before sending it
+func (self *ItemUpdate) Validate() error {
+	if self == nil {
+		return errors.New("No item update given")
+	}
+	if self.Status != "" && !self.Status.IsValid() {
+		return fmt.Errorf("Invalid item status: %s", self.Status)
+	}
+	if self.Level != "" && !self.Level.IsValid() {
+		return fmt.Errorf("Invalid item level: %s", self.Level)
+	}
+	if self.Unassign && self.AssignedUserID != 0 {
+		return errors.New("Can't both assign and unassign an item")
+	}
+	if self.Snooze != nil && !self.Snooze.Enabled && self.Snooze.Expiration != 0 {
+		return errors.New("Snooze expiration given without enabling snooze")
+	}
+	if self.Status == "" && self.Level == "" && self.Title == "" && self.AssignedUserID == 0 &&
+		!self.Unassign && self.ResolvedInVersion == "" && self.Snooze == nil {
+		return errors.New("Item update has no changes")
+	}
+	return nil
+}
+
+func (self *ItemUpdate) MarshalJSON() ([]byte, error) {
+	obj := map[string]interface{}{}
+	if len(self.Status) != 0 {
+		obj["status"] = self.Status
+	}
+	if len(self.Level) != 0 {
+		obj["level"] = self.Level
+	}
+	if len(self.Title) != 0 {
+		obj["title"] = self.Title
+	}
+	if self.AssignedUserID != 0 {
+		obj["assigned_user_id"] = self.AssignedUserID
+	} else if self.Unassign {
+		obj["assigned_user_id"] = nil
+	}
+	if len(self.ResolvedInVersion) != 0 {
+		obj["resolved_in_version"] = self.ResolvedInVersion
+	}
+	if self.Snooze != nil {
+		obj["snooze_enabled"] = self.Snooze.Enabled
+		if self.Snooze.Enabled && self.Snooze.Expiration != 0 {
+			obj["snooze_expiration_in_seconds"] = int64(self.Snooze.Expiration / time.Second)
+		}
+	}
+	return json.Marshal(obj)
+}
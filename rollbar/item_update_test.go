@@ -0,0 +1,95 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestItemUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  *ItemUpdate
+		wantErr bool
+		want    string
+	}{
+		{"nil", nil, true, ""},
+		{"empty", &ItemUpdate{}, true, ""},
+		{"bad status", &ItemUpdate{Status: "fixed"}, true, ""},
+		{"bad level", &ItemUpdate{Level: "fatal"}, true, ""},
+		{"assign and unassign", &ItemUpdate{AssignedUserID: 1, Unassign: true}, true, ""},
+		{"expiration without snooze", &ItemUpdate{Snooze: &ItemSnooze{Expiration: time.Hour}}, true, ""},
+		{
+			"resolve",
+			&ItemUpdate{Status: ITEM_RESOLVED, ResolvedInVersion: "1.2.3"},
+			false,
+			`{"resolved_in_version":"1.2.3","status":"resolved"}`,
+		},
+		{
+			"fields",
+			&ItemUpdate{Level: LV_WARNING, Title: "Slow query", AssignedUserID: 7},
+			false,
+			`{"assigned_user_id":7,"level":"warning","title":"Slow query"}`,
+		},
+		{"unassign", &ItemUpdate{Unassign: true}, false, `{"assigned_user_id":null}`},
+		{
+			"snooze",
+			&ItemUpdate{Snooze: &ItemSnooze{Enabled: true, Expiration: 90 * time.Minute}},
+			false,
+			`{"snooze_enabled":true,"snooze_expiration_in_seconds":5400}`,
+		},
+		{"unsnooze", &ItemUpdate{Snooze: &ItemSnooze{}}, false, `{"snooze_enabled":false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				data, _ := io.ReadAll(req.Body)
+				body = string(data)
+				if req.Method != "PATCH" || !strings.HasSuffix(req.URL.Path, "/item/5") {
+					t.Errorf("Expected PATCH /item/5, got %s %s", req.Method, req.URL.Path)
+				}
+				w.Write([]byte(`{"err": 0, "result": {"id": 5}}`))
+			})
+
+			resp, err := c.UpdateItem(5, tt.update)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error")
+				}
+				if body != "" {
+					t.Errorf("Expected nothing sent for an invalid update, got %s", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if resp.ID != 5 {
+				t.Errorf("Expected the updated item, got %+v", resp)
+			}
+
+			// Re-encode with sorted keys, as the order isn't fixed
+			var got interface{}
+			json.Unmarshal([]byte(body), &got)
+			if sorted, _ := json.Marshal(got); string(sorted) != tt.want {
+				t.Errorf("Expected body %s, got %s", tt.want, body)
+			}
+		})
+	}
+}
+
+func TestParseItemStatus(t *testing.T) {
+	for _, s := range []string{"active", "resolved", "muted", "archived"} {
+		if status, err := ParseItemStatus(s); err != nil || string(status) != s {
+			t.Errorf("Expected %s to parse, got %q, %v", s, status, err)
+		}
+	}
+	if _, err := ParseItemStatus("Active"); err == nil {
+		t.Errorf("Expected an error for an unknown status")
+	}
+}
@@ -1,10 +1,16 @@
 package rollbar
 
 import (
+	"bytes"
 	"encoding/json"
+	"reflect"
+	"strings"
 	"time"
 )
 
+// Fields common to all API responses. Client methods return an *APIError
+// instead of a response when Err is set, so these are only for
+// information.
 type BaseAPIResponse struct {
 	Err     int    `json:"err"`
 	Message string `json:"message"`
@@ -18,6 +24,8 @@ func (self *BaseAPIResponse) IsSuccess() bool {
 	return self.Err == 0
 }
 
+// Time that decodes from epoch seconds, as the API sends it, or from an
+// RFC 3339 string, as it's encoded
 type JSONTime struct{ time.Time }
 
 func (self JSONTime) MarshallJSON() ([]byte, error) {
@@ -25,6 +33,13 @@ func (self JSONTime) MarshallJSON() ([]byte, error) {
 }
 
 func (self *JSONTime) UnmarshalJSON(buf []byte) error {
+	if string(buf) == "null" {
+		*self = JSONTime{}
+		return nil
+	}
+	if len(buf) != 0 && buf[0] == '"' {
+		return self.Time.UnmarshalJSON(buf)
+	}
 	var epoch int64
 	err := json.Unmarshal(buf, &epoch)
 	if err == nil {
@@ -33,6 +48,121 @@ func (self *JSONTime) UnmarshalJSON(buf []byte) error {
 	return err
 }
 
+// Decode a JSON object, unmarshalling known keys into the pointers in
+// 'fields'. Keys that aren't known, or whose values don't fit the field's
+// type, are returned so that nothing is lost.
+func unmarshalObject(data []byte, fields map[string]interface{}) (CustomInfo, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var extra CustomInfo
+	for k, v := range raw {
+		if target, ok := fields[k]; ok {
+			if err := json.Unmarshal(v, target); err == nil {
+				continue
+			}
+			// Don't leave it partially decoded
+			elem := reflect.ValueOf(target).Elem()
+			elem.Set(reflect.Zero(elem.Type()))
+		}
+
+		var val interface{}
+		dec := json.NewDecoder(bytes.NewReader(v))
+		dec.UseNumber()
+		if err := dec.Decode(&val); err != nil {
+			return nil, err
+		}
+		if extra == nil {
+			extra = CustomInfo{}
+		}
+		extra[k] = val
+	}
+
+	return extra, nil
+}
+
+// Re-encode a value that was decoded from 'raw', given 'decoded' (its
+// encoding right after decoding) and 'current' (its encoding now). Values
+// that haven't changed are taken from 'raw', recursing into objects, so
+// that keys the value didn't decode and the exact form of numbers and
+// times are kept.
+func mergeRaw(raw, decoded, current json.RawMessage) json.RawMessage {
+	if bytes.Equal(decoded, current) {
+		return raw
+	}
+
+	raw_obj := map[string]json.RawMessage{}
+	decoded_obj := map[string]json.RawMessage{}
+	current_obj := map[string]json.RawMessage{}
+	if json.Unmarshal(raw, &raw_obj) != nil ||
+		json.Unmarshal(decoded, &decoded_obj) != nil ||
+		json.Unmarshal(current, &current_obj) != nil {
+		return current
+	}
+
+	merged, err := json.Marshal(mergeRawObject(raw_obj, decoded_obj, current_obj, true))
+	if err != nil {
+		return current
+	}
+	return merged
+}
+
+// mergeRaw() for the keys of an object. Keys only in 'raw' weren't
+// decoded, and are kept if 'keep_unknown' is set.
+func mergeRawObject(raw, decoded, current map[string]json.RawMessage, keep_unknown bool) map[string]json.RawMessage {
+	merged := make(map[string]json.RawMessage, len(current))
+	for k, cur := range current {
+		dec, was_decoded := decoded[k]
+		r, was_raw := raw[k]
+		switch {
+		case was_decoded && was_raw:
+			merged[k] = mergeRaw(r, dec, cur)
+		case was_decoded && bytes.Equal(dec, cur):
+			// Added by encoding (e.g. a field without omitempty), not
+			// received
+		default:
+			merged[k] = cur
+		}
+	}
+
+	if keep_unknown {
+		for k, r := range raw {
+			_, was_decoded := decoded[k]
+			if _, ok := merged[k]; !ok && !was_decoded {
+				merged[k] = r
+			}
+		}
+	}
+	return merged
+}
+
+// Get the set of JSON keys used by a struct type's fields
+func jsonKeys(typ reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.IndexByte(tag, ','); idx >= 0 {
+				tag = tag[:idx]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		keys[name] = true
+	}
+	return keys
+}
+
 func asJSON(v interface{}) string {
 	stuff, _ := json.Marshal(v)
 	return string(stuff)
@@ -0,0 +1,42 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSONTime(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	data, err := json.Marshal(JSONTime{ts})
+	if err != nil || string(data) != `"2024-05-06T07:08:09Z"` {
+		t.Errorf("Expected an RFC 3339 string, got %s (%v)", data, err)
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"epoch", "1714979289", ts, false},
+		{"string", `"2024-05-06T07:08:09Z"`, ts, false},
+		{"encoded", string(data), ts, false},
+		{"null", "null", time.Time{}, false},
+		{"invalid", `"yesterday"`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jt JSONTime
+			err := json.Unmarshal([]byte(tt.json), &jt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !jt.Equal(tt.want) {
+				t.Errorf("Expected %s, got %s", tt.want, jt.Time)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package rollbarlog provides an io.Writer for the standard library's
+// log.Logger that reports logged messages to rollbar.
+package rollbarlog
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+const (
+	DEFAULT_RATE_LIMIT = 1.0
+	DEFAULT_BURST      = 10
+)
+
+// Messages starting with Prefix (case insensitive) are given Level
+type LevelRule struct {
+	Prefix string
+	Level  rollbar.NotificationLevel
+}
+
+// Rules used when WriterOptions.Rules is nil
+var DefaultRules = []LevelRule{
+	{"CRITICAL", rollbar.LV_CRITICAL},
+	{"FATAL", rollbar.LV_CRITICAL},
+	{"PANIC", rollbar.LV_CRITICAL},
+	{"ERROR", rollbar.LV_ERROR},
+	{"WARN", rollbar.LV_WARNING},
+	{"INFO", rollbar.LV_INFO},
+	{"DEBUG", rollbar.LV_DEBUG},
+}
+
+// Options for the writer
+type WriterOptions struct {
+	// The prefix and flags the log.Logger is configured with, so they can
+	// be stripped from each message
+	Prefix string
+	Flags  int
+
+	// Rules checked in order against each (stripped) message. Defaults to
+	// DefaultRules
+	Rules []LevelRule
+
+	// Level for messages that match no rule. Defaults to LV_INFO, so
+	// that they're only added as telemetry
+	DefaultLevel rollbar.NotificationLevel
+
+	// Messages below this level are only added as telemetry. Defaults
+	// to LV_ERROR
+	MinLevel rollbar.NotificationLevel
+
+	// Notifications allowed per second, with bursts of up to Burst.
+	// Default to DEFAULT_RATE_LIMIT and DEFAULT_BURST. Messages over the
+	// limit are dropped, and the count is added to the next notification
+	// sent.
+	RateLimit float64
+	Burst     int
+
+	// Optional writer that also receives all output
+	Writer io.Writer
+}
+
+// io.Writer that sends messages written by a log.Logger to rollbar
+type Writer struct {
+	client  rollbar.Client
+	options WriterOptions
+	header  *regexp.Regexp
+
+	lock    sync.Mutex
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+var levelRank = map[rollbar.NotificationLevel]int{
+	rollbar.LV_DEBUG:    0,
+	rollbar.LV_INFO:     1,
+	rollbar.LV_WARNING:  2,
+	rollbar.LV_ERROR:    3,
+	rollbar.LV_CRITICAL: 4,
+}
+
+// Create a new writer. 'options' may be nil to use the defaults.
+func NewWriter(client rollbar.Client, options *WriterOptions) *Writer {
+	self := &Writer{client: client}
+	if options != nil {
+		self.options = *options
+	}
+	if self.options.Rules == nil {
+		self.options.Rules = DefaultRules
+	}
+	if self.options.DefaultLevel == "" {
+		self.options.DefaultLevel = rollbar.LV_INFO
+	}
+	if self.options.MinLevel == "" {
+		self.options.MinLevel = rollbar.LV_ERROR
+	}
+	if self.options.RateLimit <= 0 {
+		self.options.RateLimit = DEFAULT_RATE_LIMIT
+	}
+	if self.options.Burst <= 0 {
+		self.options.Burst = DEFAULT_BURST
+	}
+	self.header = headerRegexp(self.options.Prefix, self.options.Flags)
+	self.tokens = float64(self.options.Burst)
+	return self
+}
+
+// Create a log.Logger that writes to a new Writer. The logger's prefix
+// and flags come from 'options'.
+func NewLogger(client rollbar.Client, options *WriterOptions) *log.Logger {
+	w := NewWriter(client, options)
+	return log.New(w, w.options.Prefix, w.options.Flags)
+}
+
+// Build a regexp matching the header log.Logger puts before each message
+func headerRegexp(prefix string, flags int) *regexp.Regexp {
+	pat := "^"
+	if flags&log.Lmsgprefix == 0 {
+		pat += regexp.QuoteMeta(prefix)
+	}
+	if flags&log.Ldate != 0 {
+		pat += `\d{4}/\d{2}/\d{2} `
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		pat += `\d{2}:\d{2}:\d{2}`
+		if flags&log.Lmicroseconds != 0 {
+			pat += `\.\d{6}`
+		}
+		pat += " "
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		pat += `(?P<file>.+?:\d+): `
+	}
+	if flags&log.Lmsgprefix != 0 {
+		pat += regexp.QuoteMeta(prefix)
+	}
+	return regexp.MustCompile(pat)
+}
+
+func (self *Writer) Write(p []byte) (int, error) {
+	if self.options.Writer != nil {
+		if n, err := self.options.Writer.Write(p); err != nil {
+			return n, err
+		}
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	// log.Logger writes each message with a single call, so a message
+	// spanning lines (e.g. with a stack dump) is kept together
+	self.handleMessage(strings.TrimRight(string(p), "\r\n"))
+
+	return len(p), nil
+}
+
+// Determine the level for a message
+func (self *Writer) level(message string) rollbar.NotificationLevel {
+	for _, rule := range self.options.Rules {
+		if len(message) >= len(rule.Prefix) && strings.EqualFold(message[:len(rule.Prefix)], rule.Prefix) {
+			return rule.Level
+		}
+	}
+	return self.options.DefaultLevel
+}
+
+// Take a token from the bucket. Must be called with the lock held.
+func (self *Writer) allow() bool {
+	now := time.Now()
+	if !self.last.IsZero() {
+		self.tokens += now.Sub(self.last).Seconds() * self.options.RateLimit
+		if max := float64(self.options.Burst); self.tokens > max {
+			self.tokens = max
+		}
+	}
+	self.last = now
+
+	if self.tokens < 1 {
+		return false
+	}
+	self.tokens--
+	return true
+}
+
+func (self *Writer) handleMessage(message string) {
+	custom := rollbar.CustomInfo{}
+
+	if m := self.header.FindStringSubmatchIndex(message); m != nil {
+		if file_idx := self.header.SubexpIndex("file"); file_idx >= 0 && m[2*file_idx] >= 0 {
+			custom["file"] = message[m[2*file_idx]:m[2*file_idx+1]]
+		}
+		message = message[m[1]:]
+	}
+
+	if strings.TrimSpace(message) == "" {
+		return
+	}
+
+	level := self.level(message)
+	if levelRank[level] < levelRank[self.options.MinLevel] {
+		self.client.AddTelemetry(rollbar.NewLogTelemetry(level, message, custom))
+		return
+	}
+
+	if !self.allow() {
+		self.dropped++
+		return
+	}
+
+	if self.dropped != 0 {
+		custom["dropped_lines"] = self.dropped
+		self.dropped = 0
+	}
+
+	notif := self.client.NewMessageNotification(level, message, custom)
+
+	rollbar.SendNotificationAsync(self.client, notif)
+}
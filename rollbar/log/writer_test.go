@@ -0,0 +1,143 @@
+package rollbarlog_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	rollbarlog "github.com/comstud/go-rollbar/rollbar/log"
+	"github.com/comstud/go-rollbar/rollbar/rollbartest"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *rollbarlog.WriterOptions
+		lines   []string
+		want    []string
+	}{
+		{
+			"default level",
+			&rollbarlog.WriterOptions{DefaultLevel: rollbar.LV_ERROR},
+			[]string{"something broke"},
+			[]string{`error "something broke"`},
+		},
+		{
+			"level rules",
+			nil,
+			[]string{"INFO started", "warning: slow", "FATAL: out of disk"},
+			[]string{`critical "FATAL: out of disk"`},
+		},
+		{
+			"min level",
+			&rollbarlog.WriterOptions{MinLevel: rollbar.LV_WARNING},
+			[]string{"INFO started", "warning: slow"},
+			[]string{`warning "warning: slow"`},
+		},
+		{
+			"prefix and flags stripped",
+			&rollbarlog.WriterOptions{Prefix: "app: ", Flags: log.LstdFlags | log.Lshortfile},
+			[]string{"ERROR failed"},
+			[]string{`error "ERROR failed" file=writer_test.go`},
+		},
+		{
+			"message prefix",
+			&rollbarlog.WriterOptions{Prefix: "[db] ", Flags: log.Ltime | log.Lmsgprefix},
+			[]string{"ERROR lost connection"},
+			[]string{`error "ERROR lost connection"`},
+		},
+		{
+			"multi-line and blank",
+			nil,
+			[]string{"ERROR failed\ngoroutine 1 [running]:\nmain.main()", ""},
+			[]string{`error "ERROR failed\ngoroutine 1 [running]:\nmain.main()"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := rollbartest.NewRecorder()
+			logger := rollbarlog.NewLogger(recorder, tt.options)
+			for _, line := range tt.lines {
+				logger.Print(line)
+			}
+
+			var got []string
+			for _, notif := range recorder.Notifications() {
+				desc := fmt.Sprintf("%s %q", notif.GetLevel(), notif.GetTitle())
+				if file, ok := notif.GetCustom()["file"].(string); ok {
+					desc += " file=" + strings.SplitN(file, ":", 2)[0]
+				}
+				got = append(got, desc)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// Recorder that also keeps the telemetry added to it
+type telemetryRecorder struct {
+	*rollbartest.Recorder
+	telemetry []*rollbar.NotifierTelemetry
+}
+
+func (self *telemetryRecorder) AddTelemetry(entry *rollbar.NotifierTelemetry) {
+	self.telemetry = append(self.telemetry, entry)
+}
+
+func TestWriterUnprefixedLine(t *testing.T) {
+	recorder := &telemetryRecorder{Recorder: rollbartest.NewRecorder()}
+	logger := log.New(rollbarlog.NewWriter(recorder, nil), "", 0)
+	logger.Print("listening on :8080")
+
+	if n := len(recorder.Notifications()); n != 0 {
+		t.Errorf("Expected nothing reported, got %d notifications", n)
+	}
+	if len(recorder.telemetry) != 1 {
+		t.Fatalf("Expected 1 telemetry entry, got %d", len(recorder.telemetry))
+	}
+	if entry := recorder.telemetry[0]; entry.Level != rollbar.LV_INFO || entry.Body["message"] != "listening on :8080" {
+		t.Errorf("Expected the line as info telemetry, got %s %v", entry.Level, entry.Body)
+	}
+}
+
+func TestWriterRateLimit(t *testing.T) {
+	recorder := rollbartest.NewRecorder()
+	logger := rollbarlog.NewLogger(recorder, &rollbarlog.WriterOptions{RateLimit: 0.001, Burst: 2})
+
+	for i := 0; i < 5; i++ {
+		logger.Printf("ERROR %d", i)
+	}
+
+	notifs := recorder.Notifications()
+	if len(notifs) != 2 {
+		t.Fatalf("Expected the burst of 2 notifications, got %d", len(notifs))
+	}
+	if _, ok := notifs[1].GetCustom()["dropped_lines"]; ok {
+		t.Errorf("Expected no dropped lines before any were dropped")
+	}
+}
+
+func TestWriterReportsDroppedLines(t *testing.T) {
+	recorder := rollbartest.NewRecorder()
+	logger := rollbarlog.NewLogger(recorder, &rollbarlog.WriterOptions{RateLimit: 20, Burst: 1})
+
+	for i := 0; i < 3; i++ {
+		logger.Printf("ERROR %d", i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	logger.Print("ERROR later")
+
+	notifs := recorder.Notifications()
+	if len(notifs) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(notifs))
+	}
+	if dropped := notifs[1].GetCustom()["dropped_lines"]; dropped != 2 {
+		t.Errorf("Expected 2 dropped lines, got %v", dropped)
+	}
+}
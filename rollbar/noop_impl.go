@@ -1,9 +1,16 @@
 package rollbar
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 var errNotImpl = errors.New("Not implemented")
 
+func noopPage[T any](page uint64) ([]T, error) {
+	return nil, errNotImpl
+}
+
 type noopClient struct {
 	apiBaseURL string
 }
@@ -25,14 +32,34 @@ func (self *noopClient) GetItemByCounter(counter uint64) (*ItemResponse, error)
 	return nil, errNotImpl
 }
 
-func (self *noopClient) SetItemStatus(id uint64, status string) error {
+func (self *noopClient) SetItemStatus(id uint64, status ItemStatus) error {
 	return errNotImpl
 }
 
-func (self *noopClient) SetItemStatusByCounter(counter uint64, status string) error {
+func (self *noopClient) SetItemStatusByCounter(counter uint64, status ItemStatus) error {
 	return errNotImpl
 }
 
+func (self *noopClient) UpdateItem(id uint64, update *ItemUpdate) (*ItemResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) UpdateItemByCounter(counter uint64, update *ItemUpdate) (*ItemResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) ListItems(filter *ItemFilter) (*ItemsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) ListItemsWithPage(filter *ItemFilter, page uint64) (*ItemsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) IterItems(filter *ItemFilter) *ItemIterator {
+	return &ItemIterator{pager: NewPager(noopPage[*Item], nil, nil)}
+}
+
 func (self *noopClient) GetItemOccurrences(item_id uint64) (*OccurrencesResponse, error) {
 	return nil, errNotImpl
 }
@@ -53,8 +80,177 @@ func (self *noopClient) GetOccurrencesWithPage(page uint64) (*OccurrencesRespons
 	return nil, errNotImpl
 }
 
+func (self *noopClient) GetTopActiveItems(filter *ReportFilter) (*TopActiveItemsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetOccurrenceCounts(filter *ReportFilter) (*ReportCountsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetActivatedCounts(filter *ReportFilter) (*ReportCountsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) RecordDeploy(deploy *DeployRequest) (*RecordDeployResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) SetDeployStatus(id uint64, status DeployStatus) (*DeployResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetDeploy(id uint64) (*DeployResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetDeploys() (*DeploysResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetDeploysWithPage(page uint64) (*DeploysResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetProjects() (*ProjectsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetProject(id uint64) (*ProjectResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) CreateProject(name string) (*ProjectResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) DeleteProject(id uint64) error {
+	return errNotImpl
+}
+
+func (self *noopClient) GetProjectAccessTokens(project_id uint64) (*AccessTokensResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) CreateProjectAccessToken(project_id uint64, token *AccessTokenRequest) (*AccessTokenResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetTeams() (*TeamsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetTeam(id uint64) (*TeamResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) CreateTeam(name string, access_level TeamAccessLevel) (*TeamResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) DeleteTeam(id uint64) error {
+	return errNotImpl
+}
+
+func (self *noopClient) GetTeamUsers(team_id uint64) (*TeamUsersResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetTeamUsersWithPage(team_id uint64, page uint64) (*TeamUsersResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) AddTeamUser(team_id uint64, user_id uint64) error {
+	return errNotImpl
+}
+
+func (self *noopClient) RemoveTeamUser(team_id uint64, user_id uint64) error {
+	return errNotImpl
+}
+
+func (self *noopClient) GetTeamProjects(team_id uint64) (*TeamProjectsResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) AddTeamProject(team_id uint64, project_id uint64) error {
+	return errNotImpl
+}
+
+func (self *noopClient) RemoveTeamProject(team_id uint64, project_id uint64) error {
+	return errNotImpl
+}
+
+func (self *noopClient) InviteTeamUser(team_id uint64, email string) (*InviteResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetTeamInvitesWithPage(team_id uint64, page uint64) (*InvitesResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetUsers() (*UsersResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetUser(id uint64) (*UserResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) CreateRQLJob(ctx context.Context, query string, force_refresh bool) (*RQLJobResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetRQLJob(ctx context.Context, id uint64) (*RQLJobResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) GetRQLJobResult(ctx context.Context, id uint64) (*RQLJobResultResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) CancelRQLJob(ctx context.Context, id uint64) (*RQLJobResponse, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) RunRQLQuery(ctx context.Context, query string, options *RQLOptions) (*RQLJobResult, error) {
+	return nil, errNotImpl
+}
+
+func (self *noopClient) ItemsPager(filter *ItemFilter, options *PagerOptions) *Pager[*Item] {
+	return NewPager(noopPage[*Item], nil, options)
+}
+
+func (self *noopClient) OccurrencesPager(options *PagerOptions) *Pager[*Occurrence] {
+	return NewPager(noopPage[*Occurrence], nil, options)
+}
+
+func (self *noopClient) ItemOccurrencesPager(item_id uint64, options *PagerOptions) *Pager[*Occurrence] {
+	return NewPager(noopPage[*Occurrence], nil, options)
+}
+
+func (self *noopClient) DeploysPager(options *PagerOptions) *Pager[*Deploy] {
+	return NewPager(noopPage[*Deploy], nil, options)
+}
+
+func (self *noopClient) TeamUsersPager(team_id uint64, options *PagerOptions) *Pager[*TeamUser] {
+	return NewPager(noopPage[*TeamUser], nil, options)
+}
+
+func (self *noopClient) TeamInvitesPager(team_id uint64, options *PagerOptions) *Pager[*Invite] {
+	return NewPager(noopPage[*Invite], nil, options)
+}
+
+func (self *noopClient) WatchOccurrences(ctx context.Context, filter *WatchFilter) <-chan *WatchEvent {
+	ch := make(chan *WatchEvent, 1)
+	ch <- &WatchEvent{Err: errNotImpl}
+	close(ch)
+	return ch
+}
+
 func (self *noopClient) NewMessageNotification(level NotificationLevel, message string, custom CustomInfo) *MessageNotification {
-	return NewMessageNotification(level, message, custom)
+	notif := NewMessageNotification(level, message, custom)
+	notif.notifierMessageBody.Message.Body = message
+	return notif
 }
 
 func (self *noopClient) NewTraceNotification(level NotificationLevel, message string, custom CustomInfo) *TraceNotification {
@@ -69,12 +265,39 @@ func (self *noopClient) NewCrashReportNotification(level NotificationLevel, mess
 	return NewCrashReportNotification(level, message, custom)
 }
 
+func (self *noopClient) NewMessageNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *MessageNotification {
+	notif := self.NewMessageNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
+func (self *noopClient) NewTraceNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *TraceNotification {
+	notif := NewTraceNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
+func (self *noopClient) NewTraceChainNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *TraceChainNotification {
+	notif := NewTraceChainNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
+func (self *noopClient) NewCrashReportNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *CrashReportNotification {
+	notif := NewCrashReportNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
 func (self *noopClient) SendNotification(notif Notification) (*NotificationResponse, error) {
 	res := &NotificationResponse{Err: 0}
 	res.Result.UUID = "fake-uuid"
 	return res, nil
 }
 
+func (self *noopClient) AddTelemetry(entry *NotifierTelemetry) {
+}
+
 func (self *noopClient) Options() *ClientOptions {
 	return &ClientOptions{}
 }
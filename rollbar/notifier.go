@@ -1,6 +1,11 @@
 package rollbar
 
-import "time"
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
 
 type NotificationLevel string
 
@@ -12,8 +17,55 @@ const (
 	LV_DEBUG    NotificationLevel = NotificationLevel("debug")
 )
 
+// Is the level one rollbar accepts?
+func (self NotificationLevel) IsValid() bool {
+	switch self {
+	case LV_CRITICAL, LV_ERROR, LV_WARNING, LV_INFO, LV_DEBUG:
+		return true
+	}
+	return false
+}
+
+// Rollbar's numeric levels, as used by some API endpoints
+var numericLevels = map[int]NotificationLevel{
+	10: LV_DEBUG,
+	20: LV_INFO,
+	30: LV_WARNING,
+	40: LV_ERROR,
+	50: LV_CRITICAL,
+}
+
+// Decode a level given either by name or by number
+func (self *NotificationLevel) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var num int
+	if err := json.Unmarshal(data, &num); err == nil {
+		if level, ok := numericLevels[num]; ok {
+			*self = level
+		} else {
+			// Keep levels rollbar adds later rather than failing the
+			// whole response
+			*self = NotificationLevel(strconv.Itoa(num))
+		}
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*self = NotificationLevel(name)
+	return nil
+}
+
 type CustomInfo map[string]interface{}
 
+// Implemented by the notifications Client's New*Notification() methods
+// create. Methods may be added to it, so other implementations should
+// embed one of those.
 type Notification interface {
 	GetEnvironment() string
 	SetEnvironment(env string)
@@ -49,6 +101,35 @@ type Notification interface {
 	SetUUID(uuid string) Notification
 	GetNotifier() *NotifierLibrary
 	SetNotifier(notifier *NotifierLibrary) Notification
+	GetTelemetry() []*NotifierTelemetry
+	SetTelemetry(telemetry []*NotifierTelemetry) Notification
+	GetAccessToken() string
+	SetAccessToken(token string) Notification
+}
+
+// Implemented by clients that handle SendNotificationAsync() themselves,
+// e.g. to record notifications synchronously in tests
+type AsyncSender interface {
+	SendNotificationAsync(notif Notification)
+}
+
+// Send a notification in the background so that reporting never blocks
+// the caller, logging any error to the client's Logger. This is how the
+// integrations send. Clients implementing AsyncSender do the send
+// themselves.
+func SendNotificationAsync(client Client, notif Notification) {
+	if sender, ok := client.(AsyncSender); ok {
+		sender.SendNotificationAsync(notif)
+		return
+	}
+
+	go func() {
+		if _, err := client.SendNotification(notif); err != nil {
+			if logger := client.Options().Logger; logger != nil {
+				logger.Printf("Error sending notification to rollbar: %s", err)
+			}
+		}
+	}()
 }
 
 // NotificationResponse contains the API response for posting an item
@@ -100,12 +181,27 @@ func (self *client) NewCrashReportNotification(level NotificationLevel, message
 }
 
 func (self *client) SendNotification(notif Notification) (*NotificationResponse, error) {
+	if notif.GetTelemetry() == nil {
+		notif.SetTelemetry(self.telemetry.copy())
+	}
+
+	token := notif.GetAccessToken()
+	if token == "" {
+		var err error
+		if token, err = self.tokenFor(scopePostServerItem); err != nil {
+			return nil, err
+		}
+	}
+
 	notif_resp := &NotificationResponse{}
-	err := self.httpPost(
+	err := self.httpCallWithToken(
+		context.Background(),
+		token,
+		"POST",
 		"/item/",
+		nil,
 		map[string]interface{}{
-			"access_token": self.accessToken,
-			"data":         notif,
+			"data": notif,
 		},
 		&notif_resp,
 	)
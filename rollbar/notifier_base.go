@@ -41,6 +41,10 @@ type baseNotification struct {
 
 	// Optional info that describes the library used to send event
 	Notifier *NotifierLibrary `json:"notifier,omitempty"`
+
+	// post_server_item token to send with, overriding the client's.
+	// Not part of the payload.
+	accessToken string
 }
 
 func (self *baseNotification) GetEnvironment() string {
@@ -185,6 +189,17 @@ func (self *baseNotification) SetUUID(uuid string) Notification {
 	return self.self
 }
 
+func (self *baseNotification) GetAccessToken() string {
+	return self.accessToken
+}
+
+// Send to a different project than the client's by using its
+// post_server_item token
+func (self *baseNotification) SetAccessToken(token string) Notification {
+	self.accessToken = token
+	return self.self
+}
+
 func (self *baseNotification) GetNotifier() *NotifierLibrary {
 	return self.Notifier
 }
@@ -215,6 +230,15 @@ func (self *NotifierClient) MarshalJSON() ([]byte, error) {
 	return json.Marshal(obj)
 }
 
+func (self *NotifierClient) UnmarshalJSON(data []byte) error {
+	*self = NotifierClient{}
+	custom, err := unmarshalObject(data, map[string]interface{}{
+		"javascript": &self.Javascript,
+	})
+	self.Custom = custom
+	return err
+}
+
 // Javascript info to use in NotifierClient
 type NotifierJavascriptClient struct {
 	Browser             string `json:"browser,omitempty"`
@@ -248,6 +272,18 @@ func (self *NotifierJavascriptClient) MarshalJSON() ([]byte, error) {
 	return json.Marshal(obj)
 }
 
+func (self *NotifierJavascriptClient) UnmarshalJSON(data []byte) error {
+	*self = NotifierJavascriptClient{}
+	custom, err := unmarshalObject(data, map[string]interface{}{
+		"browser":               &self.Browser,
+		"code_version":          &self.CodeVersion,
+		"source_map_enabled":    &self.SourceMapEnabled,
+		"guess_uncaught_frames": &self.GuessUncaughtFrames,
+	})
+	self.Custom = custom
+	return err
+}
+
 // Optional user affected by event. Rollbar indexes by ID, username, and
 // email. ID is unique. Most recent username,email used for an ID will
 // replace older data for the ID.
@@ -280,6 +316,17 @@ func (self *NotifierPerson) MarshalJSON() ([]byte, error) {
 	return json.Marshal(obj)
 }
 
+func (self *NotifierPerson) UnmarshalJSON(data []byte) error {
+	*self = NotifierPerson{}
+	custom, err := unmarshalObject(data, map[string]interface{}{
+		"id":       &self.ID,
+		"username": &self.Username,
+		"email":    &self.Email,
+	})
+	self.Custom = custom
+	return err
+}
+
 // Optional data about the request event occurred in. Can be any arbitrary
 // key/value. Methods on NotifierRequest() exist for keys that rollbar
 // understands.
@@ -352,6 +399,23 @@ func (self *NotifierRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(obj)
 }
 
+func (self *NotifierRequest) UnmarshalJSON(data []byte) error {
+	*self = NotifierRequest{}
+	custom, err := unmarshalObject(data, map[string]interface{}{
+		"url":          &self.URL,
+		"method":       &self.Method,
+		"headers":      &self.Headers,
+		"params":       &self.Params,
+		"GET":          &self.GETParams,
+		"query_string": &self.QueryString,
+		"POST":         &self.POSTParams,
+		"body":         &self.Body,
+		"user_ip":      &self.UserIP,
+	})
+	self.Custom = custom
+	return err
+}
+
 // Optional data about the server
 type NotifierServer struct {
 	// Server hostname (will be indexed)
@@ -392,6 +456,18 @@ func (self *NotifierServer) MarshalJSON() ([]byte, error) {
 	return json.Marshal(obj)
 }
 
+func (self *NotifierServer) UnmarshalJSON(data []byte) error {
+	*self = NotifierServer{}
+	custom, err := unmarshalObject(data, map[string]interface{}{
+		"host":         &self.Host,
+		"root":         &self.Root,
+		"branch":       &self.Branch,
+		"code_version": &self.CodeVersion,
+	})
+	self.Custom = custom
+	return err
+}
+
 // Optional data about the notifier library
 type NotifierLibrary struct {
 	// Optional name describing notifier (this) library
@@ -8,6 +8,7 @@ type CrashReportNotification struct {
 // Body container for NotifierCrashReport
 type notifierCrashReportBody struct {
 	CrashReport NotifierCrashReport `json:"crash_report,omitempty"`
+	notifierTelemetryBody
 }
 
 type NotifierCrashReport struct {
@@ -9,6 +9,7 @@ type MessageNotification struct {
 
 type notifierMessageBody struct {
 	Message NotifierMessage `json:"message,omitempty"`
+	notifierTelemetryBody
 }
 
 // Object to use in NotificationData
@@ -31,3 +32,19 @@ func (self *NotifierMessage) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(obj)
 }
+
+func (self *NotifierMessage) UnmarshalJSON(data []byte) error {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	self.Body, _ = obj["body"].(string)
+	delete(obj, "body")
+
+	self.Custom = nil
+	if len(obj) != 0 {
+		self.Custom = CustomInfo(obj)
+	}
+	return nil
+}
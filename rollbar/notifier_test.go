@@ -0,0 +1,107 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNotificationLevelUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want NotificationLevel
+	}{
+		{`"error"`, LV_ERROR},
+		{`"custom"`, NotificationLevel("custom")},
+		{`10`, LV_DEBUG},
+		{`20`, LV_INFO},
+		{`30`, LV_WARNING},
+		{`40`, LV_ERROR},
+		{`50`, LV_CRITICAL},
+		{`60`, NotificationLevel("60")},
+		{`null`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var level NotificationLevel
+			if err := json.Unmarshal([]byte(tt.in), &level); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if level != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, level)
+			}
+		})
+	}
+}
+
+func TestNotificationLevelUnknownDoesNotFailResponse(t *testing.T) {
+	var item struct {
+		Level NotificationLevel `json:"level"`
+		Title string            `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(`{"level": 45, "title": "x"}`), &item); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if item.Title != "x" || item.Level.IsValid() {
+		t.Errorf("Got %+v", item)
+	}
+}
+
+func TestSetTelemetryChains(t *testing.T) {
+	telemetry := []*NotifierTelemetry{NewLogTelemetry(LV_INFO, "hi", nil)}
+	notifs := []Notification{
+		NewMessageNotification(LV_INFO, "msg", nil),
+		NewTraceNotification(LV_ERROR, "trace", nil),
+		NewTraceChainNotification(LV_ERROR, "chain", nil),
+		NewCrashReportNotification(LV_CRITICAL, "crash", nil),
+	}
+
+	for _, notif := range notifs {
+		got := notif.SetTelemetry(telemetry).SetContext("ctx")
+		if got != notif {
+			t.Errorf("%T: SetTelemetry didn't return the notification", notif)
+		}
+		if len(notif.GetTelemetry()) != 1 || notif.GetContext() != "ctx" {
+			t.Errorf("%T: telemetry or context not set", notif)
+		}
+	}
+}
+
+type sendingClient struct {
+	Client
+	sent chan Notification
+}
+
+func (self *sendingClient) SendNotification(notif Notification) (*NotificationResponse, error) {
+	self.sent <- notif
+	return &NotificationResponse{}, nil
+}
+
+type syncSendingClient struct {
+	sendingClient
+}
+
+func (self *syncSendingClient) SendNotificationAsync(notif Notification) {
+	self.SendNotification(notif)
+}
+
+func TestSendNotificationAsync(t *testing.T) {
+	notif := NewMessageNotification(LV_ERROR, "oops", nil)
+
+	client := &sendingClient{Client: NewNOOPClient(), sent: make(chan Notification)}
+	SendNotificationAsync(client, notif)
+	if got := <-client.sent; got != notif {
+		t.Errorf("Sent the wrong notification")
+	}
+
+	sync_client := &syncSendingClient{sendingClient{Client: NewNOOPClient(), sent: make(chan Notification, 1)}}
+	SendNotificationAsync(sync_client, notif)
+	select {
+	case got := <-sync_client.sent:
+		if got != notif {
+			t.Errorf("Sent the wrong notification")
+		}
+	default:
+		t.Errorf("AsyncSender wasn't used to send synchronously")
+	}
+}
@@ -20,11 +20,13 @@ type TraceChainNotification struct {
 // 'body' container for a 'trace' notification
 type notifierTraceBody struct {
 	Trace NotifierTrace `json:"trace,omitempty"`
+	notifierTelemetryBody
 }
 
 // 'body' container for a 'trace_chain' notification
 type notifierTraceChainBody struct {
 	TraceChain []*NotifierTrace `json:"trace_chain,omitempty"`
+	notifierTelemetryBody
 }
 
 // 'trace' object used in 'trace' and 'trace_chain' notifications
@@ -0,0 +1,44 @@
+package rollbar
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+//go:noinline
+func addCallerFrames(trace *NotifierTrace) {
+	trace.AddRuntimeFrames(nil)
+}
+
+func TestAddRuntimeFramesKeepsOrder(t *testing.T) {
+	pc := make([]uintptr, 100)
+	num := runtime.Callers(1, pc)
+
+	tests := []struct {
+		name string
+		add  func(trace *NotifierTrace)
+	}{
+		{"given frames", func(trace *NotifierTrace) { trace.AddRuntimeFrames(runtime.CallersFrames(pc[:num])) }},
+		{"caller's frames", addCallerFrames},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := &NotifierTrace{}
+			tt.add(trace)
+
+			frames := trace.Frames
+			if len(frames) < 2 {
+				t.Fatalf("Expected frames, got %d", len(frames))
+			}
+			// As runtime.Frames lists them
+			if first := frames[0].Method; !strings.HasPrefix(first, "github.com/comstud/go-rollbar/rollbar.TestAddRuntimeFramesKeepsOrder") {
+				t.Errorf("Expected the most recent call first, got %s", first)
+			}
+			if last := frames[len(frames)-1].Method; last != "runtime.goexit" {
+				t.Errorf("Expected the oldest call last, got %s", last)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package rollbar
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"reflect"
 )
 
 type OccurrenceMetadata struct {
@@ -18,15 +22,12 @@ type OccurrenceNotifier struct {
 	Version string `json:"version"`
 }
 
-type OccurrenceBody struct {
-	Message map[string]interface{} `json:"message"`
-}
-
 type OccurrenceData struct {
 	Environment string             `json:"environment"`
 	Timestamp   JSONTime           `json:"timestamp"`
 	CodeVersion string             `json:"code_version"`
 	Platform    string             `json:"platform"`
+	Language    string             `json:"language,omitempty"`
 	Level       string             `json:"level"`
 	Notifier    OccurrenceNotifier `json:"notifier"`
 	Context     string             `json:"context"`
@@ -35,6 +36,94 @@ type OccurrenceData struct {
 	Metadata    OccurrenceMetadata `json:"metadata"`
 	Framework   string             `json:"framework"`
 	UUID        string             `json:"uuid"`
+	Request     *NotifierRequest   `json:"request,omitempty"`
+	Person      *NotifierPerson    `json:"person,omitempty"`
+	Server      *NotifierServer    `json:"server,omitempty"`
+	Client      *NotifierClient    `json:"client,omitempty"`
+	Custom      CustomInfo         `json:"custom,omitempty"`
+	Fingerprint string             `json:"fingerprint,omitempty"`
+
+	// Any keys not decoded into the fields above, so that nothing is
+	// lost when re-encoding
+	Extra map[string]json.RawMessage `json:"-"`
+
+	// What was decoded, and how the fields encoded right after, so that
+	// unchanged values are re-encoded exactly as they were received
+	raw     json.RawMessage
+	decoded json.RawMessage
+}
+
+// OccurrenceData without its JSON methods
+type occurrenceData OccurrenceData
+
+// JSON keys decoded into OccurrenceData fields
+var occurrenceDataKeys = jsonKeys(reflect.TypeOf(OccurrenceData{}))
+
+// Numbers in Custom and Metadata.Debug are decoded as json.Number
+func (self *OccurrenceData) UnmarshalJSON(data []byte) error {
+	*self = OccurrenceData{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode((*occurrenceData)(self)); err != nil {
+		return err
+	}
+
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for k, v := range raw {
+		if !occurrenceDataKeys[k] {
+			if self.Extra == nil {
+				self.Extra = map[string]json.RawMessage{}
+			}
+			self.Extra[k] = v
+		}
+	}
+
+	decoded, err := json.Marshal((*occurrenceData)(self))
+	if err != nil {
+		return err
+	}
+	self.raw = append(json.RawMessage(nil), data...)
+	self.decoded = decoded
+	return nil
+}
+
+// Fields that haven't changed since decoding are encoded as they were
+// received, including keys inside them that weren't decoded
+func (self *OccurrenceData) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*occurrenceData)(self))
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	if self.raw != nil {
+		raw := map[string]json.RawMessage{}
+		decoded := map[string]json.RawMessage{}
+		if json.Unmarshal(self.raw, &raw) == nil && json.Unmarshal(self.decoded, &decoded) == nil {
+			// Unknown keys come from Extra instead, which may have changed
+			for k := range raw {
+				if !occurrenceDataKeys[k] {
+					delete(raw, k)
+				}
+			}
+			obj = mergeRawObject(raw, decoded, obj, false)
+		}
+	}
+
+	for k, v := range self.Extra {
+		if _, ok := obj[k]; !ok {
+			obj[k] = v
+		}
+	}
+	return json.Marshal(obj)
 }
 
 // Occurrence object as returned from API
@@ -47,9 +136,10 @@ type Occurrence struct {
 	Billable   uint           `json:"billable"`
 }
 
-// String representation of an occurrence (pretty json)
+// String representation of an occurrence: the message, or the stack
+// trace formatted like a Go panic
 func (self *Occurrence) String() string {
-	return self.AsPrettyJSON()
+	return self.Data.Body.String()
 }
 
 // Occurrence as a json string
@@ -75,7 +165,9 @@ type OccurrencesResult struct {
 
 // Full API response for multiple occurrences
 type OccurrencesResponse struct {
-	rollbar *client
+	rollbar  *client
+	itemID   uint64
+	pageSize int
 	BaseAPIResponse
 	Page               uint64
 	*OccurrencesResult `json:"result"`
@@ -88,6 +180,9 @@ func (self *OccurrencesResponse) String() string {
 
 // Occurrences response as a json string
 func (self *OccurrencesResponse) AsJSON() string {
+	if self.OccurrencesResult == nil {
+		return "[]"
+	}
 	s := "["
 	for i, occur := range self.Occurrences {
 		if i != 0 {
@@ -100,6 +195,9 @@ func (self *OccurrencesResponse) AsJSON() string {
 
 // Occurrences response as a pretty json string
 func (self *OccurrencesResponse) AsPrettyJSON() string {
+	if self.OccurrencesResult == nil {
+		return "[]"
+	}
 	s := "["
 	for i, occur := range self.Occurrences {
 		if i != 0 {
@@ -110,29 +208,42 @@ func (self *OccurrencesResponse) AsPrettyJSON() string {
 	return s + "]"
 }
 
-// Does an occurrences response have more pages?
+// Does an occurrences response have more pages? A page smaller than a
+// full one is the last.
 func (self *OccurrencesResponse) HasMorePages() bool {
-	return self.IsSuccess() && len(self.Occurrences) > 0
+	if !self.IsSuccess() || self.OccurrencesResult == nil {
+		return false
+	}
+	page_size := self.pageSize
+	return pageHasMore(len(self.Occurrences), &page_size)
 }
 
-// Get the next page of currences
+// Get the next page of occurrences
 func (self *OccurrencesResponse) GetNextPage() (*OccurrencesResponse, error) {
 	if !self.HasMorePages() {
 		return self, nil
 	}
+	if self.rollbar == nil {
+		return nil, errNoPagerClient
+	}
 	resp := &OccurrencesResponse{
-		rollbar: self.rollbar,
-		Page:    self.Page + 1,
+		rollbar:  self.rollbar,
+		itemID:   self.itemID,
+		pageSize: max(self.pageSize, len(self.Occurrences)),
+		Page:     self.Page + 1,
+	}
+	if resp.itemID != 0 {
+		return self.rollbar.getItemOccurrences(context.Background(), resp.itemID, resp)
 	}
-	return self.rollbar.getOccurrences(resp)
+	return self.rollbar.getOccurrences(context.Background(), resp)
 }
 
-func (self *client) getOccurrences(resp *OccurrencesResponse) (*OccurrencesResponse, error) {
+func (self *client) getOccurrences(ctx context.Context, resp *OccurrencesResponse) (*OccurrencesResponse, error) {
 	query := url.Values{
 		"page": []string{fmt.Sprintf("%d", resp.Page)},
 	}
 
-	err := self.httpGet("/instances", query, &resp)
+	err := self.httpCallWithContext(ctx, scopeRead, "GET", "/instances", query, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -140,12 +251,12 @@ func (self *client) getOccurrences(resp *OccurrencesResponse) (*OccurrencesRespo
 	return resp, nil
 }
 
-func (self *client) getItemOccurrences(item_id uint64, resp *OccurrencesResponse) (*OccurrencesResponse, error) {
+func (self *client) getItemOccurrences(ctx context.Context, item_id uint64, resp *OccurrencesResponse) (*OccurrencesResponse, error) {
 	query := url.Values{
 		"page": []string{fmt.Sprintf("%d", resp.Page)},
 	}
 
-	err := self.httpGet(fmt.Sprintf("/item/%d/instances", item_id), query, &resp)
+	err := self.httpCallWithContext(ctx, scopeRead, "GET", fmt.Sprintf("/item/%d/instances", item_id), query, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +269,7 @@ func (self *client) GetOccurrence(id uint64) (*OccurrenceResponse, error) {
 	occur_resp := &OccurrenceResponse{}
 
 	err := self.httpGet(
+		scopeRead,
 		fmt.Sprintf("/instance/%d", id),
 		nil,
 		&occur_resp,
@@ -172,10 +284,11 @@ func (self *client) GetOccurrence(id uint64) (*OccurrenceResponse, error) {
 // Get first page of all occurrences
 func (self *client) GetOccurrences() (*OccurrencesResponse, error) {
 	resp := &OccurrencesResponse{
-		rollbar: self,
-		Page:    1,
+		rollbar:  self,
+		pageSize: OCCURRENCES_PAGE_SIZE,
+		Page:     1,
 	}
-	return self.getOccurrences(resp)
+	return self.getOccurrences(context.Background(), resp)
 }
 
 // Get a specific page of all occurrences
@@ -184,19 +297,22 @@ func (self *client) GetOccurrencesWithPage(page uint64) (*OccurrencesResponse, e
 		return nil, errors.New("Page must be greater than 0")
 	}
 	resp := &OccurrencesResponse{
-		rollbar: self,
-		Page:    page,
+		rollbar:  self,
+		pageSize: OCCURRENCES_PAGE_SIZE,
+		Page:     page,
 	}
-	return self.getOccurrences(resp)
+	return self.getOccurrences(context.Background(), resp)
 }
 
 // Get first page of occurrences for an item (by item id -- NOT the counter)
 func (self *client) GetItemOccurrences(item_id uint64) (*OccurrencesResponse, error) {
 	resp := &OccurrencesResponse{
-		rollbar: self,
-		Page:    1,
+		rollbar:  self,
+		itemID:   item_id,
+		pageSize: OCCURRENCES_PAGE_SIZE,
+		Page:     1,
 	}
-	return self.getItemOccurrences(item_id, resp)
+	return self.getItemOccurrences(context.Background(), item_id, resp)
 }
 
 // Get a specific page of occurrences for an item (by item id -- NOT the counter)
@@ -205,8 +321,10 @@ func (self *client) GetItemOccurrencesWithPage(item_id uint64, page uint64) (*Oc
 		return nil, errors.New("Page must be greater than 0")
 	}
 	resp := &OccurrencesResponse{
-		rollbar: self,
-		Page:    page,
+		rollbar:  self,
+		itemID:   item_id,
+		pageSize: OCCURRENCES_PAGE_SIZE,
+		Page:     page,
 	}
-	return self.getItemOccurrences(item_id, resp)
+	return self.getItemOccurrences(context.Background(), item_id, resp)
 }
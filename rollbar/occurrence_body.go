@@ -0,0 +1,101 @@
+package rollbar
+
+import (
+	"fmt"
+	"strings"
+)
+
+type OccurrenceBodyKind string
+
+const (
+	BODY_UNKNOWN      OccurrenceBodyKind = OccurrenceBodyKind("")
+	BODY_MESSAGE      OccurrenceBodyKind = OccurrenceBodyKind("message")
+	BODY_TRACE        OccurrenceBodyKind = OccurrenceBodyKind("trace")
+	BODY_TRACE_CHAIN  OccurrenceBodyKind = OccurrenceBodyKind("trace_chain")
+	BODY_CRASH_REPORT OccurrenceBodyKind = OccurrenceBodyKind("crash_report")
+)
+
+// Body of an occurrence. Only one of Message, Trace, TraceChain or
+// CrashReport is set. See Kind().
+type OccurrenceBody struct {
+	Message     *NotifierMessage     `json:"message,omitempty"`
+	Trace       *NotifierTrace       `json:"trace,omitempty"`
+	TraceChain  []*NotifierTrace     `json:"trace_chain,omitempty"`
+	CrashReport *NotifierCrashReport `json:"crash_report,omitempty"`
+	Telemetry   []*NotifierTelemetry `json:"telemetry,omitempty"`
+}
+
+// Which kind of body this is
+func (self *OccurrenceBody) Kind() OccurrenceBodyKind {
+	switch {
+	case self.Trace != nil:
+		return BODY_TRACE
+	case self.TraceChain != nil:
+		return BODY_TRACE_CHAIN
+	case self.CrashReport != nil:
+		return BODY_CRASH_REPORT
+	case self.Message != nil:
+		return BODY_MESSAGE
+	}
+	return BODY_UNKNOWN
+}
+
+// Render the body. Traces are formatted like a Go panic, with the most
+// recent call first.
+func (self *OccurrenceBody) String() string {
+	switch self.Kind() {
+	case BODY_TRACE:
+		return self.Trace.String()
+	case BODY_TRACE_CHAIN:
+		traces := make([]string, 0, len(self.TraceChain))
+		for _, trace := range self.TraceChain {
+			traces = append(traces, trace.String())
+		}
+		return strings.Join(traces, "\ncaused by: ")
+	case BODY_CRASH_REPORT:
+		return self.CrashReport.Raw
+	case BODY_MESSAGE:
+		return self.Message.Body
+	}
+	return ""
+}
+
+// Render a trace like a Go panic, with the most recent call first
+func (self *NotifierTrace) String() string {
+	var sb strings.Builder
+
+	if exc := self.Exception; exc != nil {
+		sb.WriteString(exc.Class)
+		if exc.Message != "" {
+			sb.WriteString(": " + exc.Message)
+		}
+		sb.WriteString("\n")
+		if exc.Description != "" {
+			sb.WriteString(exc.Description + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	for i := len(self.Frames) - 1; i >= 0; i-- {
+		sb.WriteString(self.Frames[i].String())
+	}
+
+	return sb.String()
+}
+
+// Render a frame like a Go traceback entry
+func (self *NotifierFrame) String() string {
+	method := self.Method
+	if method == "" {
+		method = "<unknown>"
+	}
+	s := fmt.Sprintf("%s(...)\n\t%s:%d", method, self.Filename, self.Line)
+	if self.Column != 0 {
+		s += fmt.Sprintf(":%d", self.Column)
+	}
+	s += "\n"
+	if self.Code != "" {
+		s += "\t\t" + strings.TrimSpace(self.Code) + "\n"
+	}
+	return s
+}
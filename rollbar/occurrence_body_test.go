@@ -0,0 +1,57 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOccurrenceBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantKind OccurrenceBodyKind
+		want     string
+	}{
+		{"unknown", `{}`, BODY_UNKNOWN, ""},
+		{"message", `{"message": {"body": "hello"}}`, BODY_MESSAGE, "hello"},
+		{
+			"trace",
+			`{"trace": {
+				"exception": {"class": "*errors.errorString", "message": "boom", "description": "while saving"},
+				"frames": [
+					{"filename": "main.go", "lineno": 10, "method": "main.main"},
+					{"filename": "save.go", "lineno": 42, "colno": 3, "method": "main.save", "code": "  panic(err)  "}
+				]
+			}}`,
+			BODY_TRACE,
+			"*errors.errorString: boom\nwhile saving\n\n" +
+				"main.save(...)\n\tsave.go:42:3\n\t\tpanic(err)\n" +
+				"main.main(...)\n\tmain.go:10\n",
+		},
+		{
+			"trace chain",
+			`{"trace_chain": [
+				{"exception": {"class": "wrapped"}, "frames": [{"filename": "a.go", "lineno": 1}]},
+				{"exception": {"class": "cause"}, "frames": []}
+			]}`,
+			BODY_TRACE_CHAIN,
+			"wrapped\n\n<unknown>(...)\n\ta.go:1\n\ncaused by: cause\n\n",
+		},
+		{"crash report", `{"crash_report": {"raw": "fatal error: out of memory"}}`, BODY_CRASH_REPORT, "fatal error: out of memory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body OccurrenceBody
+			if err := json.Unmarshal([]byte(tt.body), &body); err != nil {
+				t.Fatalf("Error decoding: %s", err)
+			}
+			if kind := body.Kind(); kind != tt.wantKind {
+				t.Errorf("Expected kind %q, got %q", tt.wantKind, kind)
+			}
+			if got := body.String(); got != tt.want {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package rollbar
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Re-encode JSON with sorted keys and no whitespace, keeping numbers as
+// they are
+func normalizeJSON(t *testing.T, data []byte) string {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		t.Fatalf("Invalid JSON: %s\n%s", err, data)
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(normalized)
+}
+
+func TestOccurrenceDataRoundTrip(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "occurrence_data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(data *OccurrenceData)
+		// Keys of the golden file to change, and their new values
+		want map[string]interface{}
+	}{
+		{"unchanged", nil, nil},
+		{
+			"changed field",
+			func(data *OccurrenceData) { data.Level = "critical" },
+			map[string]interface{}{"level": "critical"},
+		},
+		{
+			"changed nested field",
+			func(data *OccurrenceData) { data.Metadata.APIServerHostname = "api-2" },
+			map[string]interface{}{"metadata.api_server_hostname": "api-2"},
+		},
+		{
+			"changed custom",
+			func(data *OccurrenceData) { data.Custom["ratio"] = 0.5 },
+			map[string]interface{}{"custom.ratio": 0.5},
+		},
+		{
+			"added field",
+			func(data *OccurrenceData) { data.Context = "save" },
+			map[string]interface{}{"context": "save"},
+		},
+		{
+			"removed field",
+			func(data *OccurrenceData) { data.Fingerprint = "" },
+			map[string]interface{}{"fingerprint": nil},
+		},
+		{
+			"removed extra",
+			func(data *OccurrenceData) { delete(data.Extra, "retries") },
+			map[string]interface{}{"retries": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data OccurrenceData
+			if err := json.Unmarshal(golden, &data); err != nil {
+				t.Fatalf("Error decoding: %s", err)
+			}
+			if tt.modify != nil {
+				tt.modify(&data)
+			}
+
+			encoded, err := json.Marshal(&data)
+			if err != nil {
+				t.Fatalf("Error encoding: %s", err)
+			}
+
+			// Apply the expected changes to the golden file
+			expected := map[string]interface{}{}
+			dec := json.NewDecoder(bytes.NewReader(golden))
+			dec.UseNumber()
+			if err := dec.Decode(&expected); err != nil {
+				t.Fatal(err)
+			}
+			for path, value := range tt.want {
+				obj, key := expected, path
+				if idx := bytes.IndexByte([]byte(path), '.'); idx >= 0 {
+					obj, key = expected[path[:idx]].(map[string]interface{}), path[idx+1:]
+				}
+				if value == nil {
+					delete(obj, key)
+				} else {
+					obj[key] = value
+				}
+			}
+			expected_json, _ := json.Marshal(expected)
+
+			if actual, want := normalizeJSON(t, encoded), normalizeJSON(t, expected_json); actual != want {
+				t.Errorf("Round trip mismatch\n--- expected\n%s\n--- actual\n%s", want, actual)
+			}
+		})
+	}
+}
+
+func TestOccurrenceDataDecodesNumbers(t *testing.T) {
+	var data OccurrenceData
+	if err := json.Unmarshal([]byte(`{"custom": {"id": 12345678901234567890}}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := data.Custom["id"].(json.Number); !ok || n.String() != "12345678901234567890" {
+		t.Errorf("Expected a json.Number, got %#v", data.Custom["id"])
+	}
+}
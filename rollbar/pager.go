@@ -0,0 +1,285 @@
+package rollbar
+
+import (
+	"errors"
+	"iter"
+	"time"
+)
+
+var errNoPagerClient = errors.New("Response has no client to get the next page with")
+
+// Page sizes documented by rollbar. A page smaller than these is the last.
+const (
+	ITEMS_PAGE_SIZE       = 100
+	OCCURRENCES_PAGE_SIZE = 20
+	DEPLOYS_PAGE_SIZE     = 20
+
+	// Not documented. Pages of team users and invites are assumed to be
+	// at least this big.
+	TEAM_USERS_PAGE_SIZE   = 20
+	TEAM_INVITES_PAGE_SIZE = 20
+)
+
+// Options for paging through a list endpoint
+type PagerOptions struct {
+	// Stop after this many items. 0 means no limit.
+	MaxItems int
+
+	// Stop at the first item older than this. Lists are returned newest
+	// first, so everything after it is older too. Ignored for lists
+	// without timestamps.
+	OlderThan time.Time
+
+	// Page to start from. Defaults to 1.
+	StartPage uint64
+
+	// Fetch the next page in the background while the current one is
+	// being consumed
+	Prefetch bool
+}
+
+// Pager over all items of a list endpoint. Pages are fetched as needed.
+//
+//	pager := client.OccurrencesPager(&rollbar.PagerOptions{MaxItems: 500})
+//	for occur, err := range pager.All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(occur.ID, occur.Data.Title)
+//	}
+type Pager[T any] struct {
+	// Gets a page and whether there are more after it
+	fetch     func(page uint64) ([]T, bool, error)
+	timestamp func(T) time.Time
+	options   PagerOptions
+
+	// Work out if there are more pages from page sizes, for fetch
+	// functions that can't say
+	inferMore bool
+}
+
+// Create a pager. 'fetch' gets a single page, starting at 1. As it can't
+// say if there are more pages, paging stops at an empty page or one
+// smaller than earlier ones. 'timestamp' gives an item's time for
+// PagerOptions.OlderThan and may be nil.
+func NewPager[T any](fetch func(page uint64) ([]T, error), timestamp func(T) time.Time, options *PagerOptions) *Pager[T] {
+	pager := newPager(
+		func(page uint64) ([]T, bool, error) {
+			items, err := fetch(page)
+			return items, true, err
+		},
+		timestamp,
+		options,
+	)
+	pager.inferMore = true
+	return pager
+}
+
+// Create a pager whose 'fetch' says if there are more pages
+func newPager[T any](fetch func(page uint64) ([]T, bool, error), timestamp func(T) time.Time, options *PagerOptions) *Pager[T] {
+	pager := &Pager[T]{
+		fetch:     fetch,
+		timestamp: timestamp,
+	}
+	if options != nil {
+		pager.options = *options
+	}
+	if pager.options.StartPage == 0 {
+		pager.options.StartPage = 1
+	}
+	return pager
+}
+
+type pagerResult[T any] struct {
+	items []T
+	more  bool
+	err   error
+}
+
+// Sequence of all items. An error ends the sequence after being yielded
+// with a zero item.
+func (self *Pager[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var prefetched chan pagerResult[T]
+
+		get := func(page uint64) ([]T, bool, error) {
+			if prefetched != nil {
+				res := <-prefetched
+				prefetched = nil
+				return res.items, res.more, res.err
+			}
+			return self.fetch(page)
+		}
+
+		page := self.options.StartPage
+		page_size := 0
+		count := 0
+
+		for {
+			items, more, err := get(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			if self.inferMore {
+				more = pageHasMore(len(items), &page_size)
+			} else if len(items) == 0 {
+				more = false
+			}
+			if more && self.options.Prefetch {
+				// Buffered so the fetch can finish if we stop early
+				prefetched = make(chan pagerResult[T], 1)
+				go func(page uint64, ch chan pagerResult[T]) {
+					items, more, err := self.fetch(page)
+					ch <- pagerResult[T]{items, more, err}
+				}(page+1, prefetched)
+			}
+
+			for _, item := range items {
+				if !self.options.OlderThan.IsZero() && self.timestamp != nil &&
+					self.timestamp(item).Before(self.options.OlderThan) {
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				count++
+				if self.options.MaxItems > 0 && count >= self.options.MaxItems {
+					return
+				}
+			}
+
+			if !more {
+				return
+			}
+			page++
+		}
+	}
+}
+
+// Get all items as a slice
+func (self *Pager[T]) Collect() ([]T, error) {
+	var items []T
+	for item, err := range self.All() {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Work out if there's another page after one with 'num' items. The API
+// doesn't say, but every page except the last is full, so a page smaller
+// than the largest seen is the last. 'page_size' tracks the largest seen.
+func pageHasMore(num int, page_size *int) bool {
+	if num == 0 {
+		return false
+	}
+	if num > *page_size {
+		*page_size = num
+	}
+	return num >= *page_size
+}
+
+// Pager over all occurrences
+func (self *client) OccurrencesPager(options *PagerOptions) *Pager[*Occurrence] {
+	return newPager(
+		func(page uint64) ([]*Occurrence, bool, error) {
+			resp, err := self.GetOccurrencesWithPage(page)
+			if err != nil || resp.OccurrencesResult == nil {
+				return nil, false, err
+			}
+			return resp.Occurrences, resp.HasMorePages(), nil
+		},
+		occurrenceTimestamp,
+		options,
+	)
+}
+
+// Pager over all occurrences of an item (by item id -- NOT the counter)
+func (self *client) ItemOccurrencesPager(item_id uint64, options *PagerOptions) *Pager[*Occurrence] {
+	return newPager(
+		func(page uint64) ([]*Occurrence, bool, error) {
+			resp, err := self.GetItemOccurrencesWithPage(item_id, page)
+			if err != nil || resp.OccurrencesResult == nil {
+				return nil, false, err
+			}
+			return resp.Occurrences, resp.HasMorePages(), nil
+		},
+		occurrenceTimestamp,
+		options,
+	)
+}
+
+// Pager over all items matching 'filter' (which may be nil)
+func (self *client) ItemsPager(filter *ItemFilter, options *PagerOptions) *Pager[*Item] {
+	return newPager(
+		func(page uint64) ([]*Item, bool, error) {
+			resp, err := self.ListItemsWithPage(filter, page)
+			if err != nil || resp.ItemsResult == nil {
+				return nil, false, err
+			}
+			return resp.Items, resp.HasMorePages(), nil
+		},
+		func(item *Item) time.Time {
+			return item.LastOccurenceTimestamp.Time
+		},
+		options,
+	)
+}
+
+// Pager over all deploys
+func (self *client) DeploysPager(options *PagerOptions) *Pager[*Deploy] {
+	return newPager(
+		func(page uint64) ([]*Deploy, bool, error) {
+			resp, err := self.GetDeploysWithPage(page)
+			if err != nil || resp.DeploysResult == nil {
+				return nil, false, err
+			}
+			return resp.Deploys, resp.HasMorePages(), nil
+		},
+		func(deploy *Deploy) time.Time {
+			return deploy.StartTime.Time
+		},
+		options,
+	)
+}
+
+// Pager over all users of a team
+func (self *client) TeamUsersPager(team_id uint64, options *PagerOptions) *Pager[*TeamUser] {
+	return newPager(
+		func(page uint64) ([]*TeamUser, bool, error) {
+			resp, err := self.GetTeamUsersWithPage(team_id, page)
+			if err != nil {
+				return nil, false, err
+			}
+			return resp.TeamUsers, resp.HasMorePages(), nil
+		},
+		nil,
+		options,
+	)
+}
+
+// Pager over all invites of a team
+func (self *client) TeamInvitesPager(team_id uint64, options *PagerOptions) *Pager[*Invite] {
+	return newPager(
+		func(page uint64) ([]*Invite, bool, error) {
+			resp, err := self.GetTeamInvitesWithPage(team_id, page)
+			if err != nil {
+				return nil, false, err
+			}
+			return resp.Invites, resp.HasMorePages(), nil
+		},
+		func(invite *Invite) time.Time {
+			return invite.DateCreated.Time
+		},
+		options,
+	)
+}
+
+func occurrenceTimestamp(occur *Occurrence) time.Time {
+	return occur.Timestamp.Time
+}
@@ -0,0 +1,256 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Fetch function over 'sizes' pages of ints, counting fetches
+func fakePages(sizes []int, fetches *int) func(page uint64) ([]int, error) {
+	return func(page uint64) ([]int, error) {
+		*fetches++
+		if int(page) > len(sizes) {
+			return nil, nil
+		}
+		start := 0
+		for _, size := range sizes[:page-1] {
+			start += size
+		}
+		items := make([]int, sizes[page-1])
+		for i := range items {
+			items[i] = start + i
+		}
+		return items, nil
+	}
+}
+
+func TestNewPagerInfersLastPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		sizes   []int
+		options *PagerOptions
+		items   int
+		fetches int
+	}{
+		{"short last page", []int{3, 3, 1}, nil, 7, 3},
+		{"full last page", []int{3, 3}, nil, 6, 3},
+		{"empty", nil, nil, 0, 1},
+		{"max items", []int{3, 3, 3}, &PagerOptions{MaxItems: 4}, 4, 2},
+		{"start page", []int{3, 3, 1}, &PagerOptions{StartPage: 2}, 4, 2},
+		{"prefetch", []int{3, 3, 1}, &PagerOptions{Prefetch: true}, 7, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			items, err := NewPager(fakePages(tt.sizes, &fetches), nil, tt.options).Collect()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(items) != tt.items {
+				t.Errorf("Expected %d items, got %d", tt.items, len(items))
+			}
+			for i := 1; i < len(items); i++ {
+				if items[i] != items[i-1]+1 {
+					t.Fatalf("Items out of order: %v", items)
+				}
+			}
+			if fetches != tt.fetches {
+				t.Errorf("Expected %d fetches, got %d", tt.fetches, fetches)
+			}
+		})
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	fetch_err := errors.New("boom")
+	pager := NewPager(func(page uint64) ([]int, error) {
+		if page == 2 {
+			return nil, fetch_err
+		}
+		return []int{1, 2}, nil
+	}, nil, nil)
+
+	items, err := pager.Collect()
+	if !errors.Is(err, fetch_err) || len(items) != 2 {
+		t.Errorf("Expected 2 items and the fetch error, got %v, %v", items, err)
+	}
+}
+
+func TestPagerOlderThan(t *testing.T) {
+	now := time.Now()
+	pager := NewPager(
+		func(page uint64) ([]time.Time, error) {
+			return []time.Time{now, now.Add(-time.Hour), now.Add(-2 * time.Hour)}, nil
+		},
+		func(ts time.Time) time.Time { return ts },
+		&PagerOptions{OlderThan: now.Add(-90 * time.Minute)},
+	)
+
+	items, err := pager.Collect()
+	if err != nil || len(items) != 2 {
+		t.Errorf("Expected 2 items, got %d (%v)", len(items), err)
+	}
+}
+
+// Serve 'total' occurrences, items, team users or invites in pages of 'page_size', counting
+// requests
+func newPagingClient(t *testing.T, total int, page_size int, requests *int32) *client {
+	return newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(requests, 1)
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		start := (page - 1) * page_size
+		n := min(max(total-start, 0), page_size)
+
+		var result map[string]interface{}
+		switch req.URL.Path {
+		case "/instances":
+			occurs := make([]*Occurrence, n)
+			for i := range occurs {
+				occurs[i] = &Occurrence{ID: uint64(total - start - i)}
+			}
+			result = map[string]interface{}{"instances": occurs}
+		case "/items":
+			items := make([]*Item, n)
+			for i := range items {
+				items[i] = &Item{ID: uint64(start + i + 1)}
+			}
+			result = map[string]interface{}{"items": items, "page": page, "total_count": total}
+		case "/team/1/users":
+			users := make([]*TeamUser, n)
+			for i := range users {
+				users[i] = &TeamUser{TeamID: 1, UserID: uint64(start + i + 1)}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"err": 0, "result": users})
+			return
+		case "/team/1/invites":
+			invites := make([]*Invite, n)
+			for i := range invites {
+				invites[i] = &Invite{ID: uint64(start + i + 1), TeamID: 1}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"err": 0, "result": invites})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"err": 0, "result": result})
+	})
+}
+
+func TestClientPagersUseDocumentedPageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		requests int32
+	}{
+		{"empty", 0, 1},
+		{"partial page", 5, 1},
+		{"full page", OCCURRENCES_PAGE_SIZE, 2},
+		{"page and a bit", OCCURRENCES_PAGE_SIZE + 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newPagingClient(t, tt.total, OCCURRENCES_PAGE_SIZE, &requests)
+			occurs, err := c.OccurrencesPager(nil).Collect()
+			if err != nil || len(occurs) != tt.total {
+				t.Fatalf("Expected %d occurrences, got %d (%v)", tt.total, len(occurs), err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestTeamPagersStopAtShortPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		requests int32
+	}{
+		{"empty", 0, 1},
+		{"partial page", 5, 1},
+		{"full page", TEAM_USERS_PAGE_SIZE, 2},
+		{"page and a bit", TEAM_USERS_PAGE_SIZE + 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newPagingClient(t, tt.total, TEAM_USERS_PAGE_SIZE, &requests)
+			users, err := c.TeamUsersPager(1, nil).Collect()
+			if err != nil || len(users) != tt.total {
+				t.Fatalf("Expected %d users, got %d (%v)", tt.total, len(users), err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d user requests, got %d", tt.requests, requests)
+			}
+
+			requests = 0
+			invites, err := c.TeamInvitesPager(1, nil).Collect()
+			if err != nil || len(invites) != tt.total {
+				t.Fatalf("Expected %d invites, got %d (%v)", tt.total, len(invites), err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d invite requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestItemsPagerUsesTotalCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		requests int32
+	}{
+		{"partial page", 5, 1},
+		{"full page", ITEMS_PAGE_SIZE, 1},
+		{"two full pages", 2 * ITEMS_PAGE_SIZE, 2},
+		{"page and a bit", ITEMS_PAGE_SIZE + 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newPagingClient(t, tt.total, ITEMS_PAGE_SIZE, &requests)
+			items, err := c.ItemsPager(nil, nil).Collect()
+			if err != nil || len(items) != tt.total {
+				t.Fatalf("Expected %d items, got %d (%v)", tt.total, len(items), err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestIterItems(t *testing.T) {
+	for _, total := range []int{0, 5, ITEMS_PAGE_SIZE, ITEMS_PAGE_SIZE + 1} {
+		var requests int32
+		c := newPagingClient(t, total, ITEMS_PAGE_SIZE, &requests)
+
+		iter := c.IterItems(nil)
+		count := 0
+		for iter.Next() {
+			count++
+			if iter.Item().ID != uint64(count) {
+				t.Fatalf("Expected item %d, got %d", count, iter.Item().ID)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if count != total {
+			t.Errorf("Expected %d items, got %d", total, count)
+		}
+		if iter.Next() {
+			t.Errorf("Next() after the end returned true")
+		}
+	}
+}
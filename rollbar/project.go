@@ -0,0 +1,223 @@
+package rollbar
+
+import (
+	"errors"
+	"fmt"
+)
+
+type AccessTokenScope string
+
+const (
+	SCOPE_READ             AccessTokenScope = AccessTokenScope("read")
+	SCOPE_WRITE            AccessTokenScope = AccessTokenScope("write")
+	SCOPE_POST_SERVER_ITEM AccessTokenScope = AccessTokenScope("post_server_item")
+	SCOPE_POST_CLIENT_ITEM AccessTokenScope = AccessTokenScope("post_client_item")
+)
+
+// Project object as returned from API
+type Project struct {
+	ID           uint64   `json:"id"`
+	AccountID    uint64   `json:"account_id"`
+	Name         string   `json:"name"`
+	Status       string   `json:"status"`
+	DateCreated  JSONTime `json:"date_created"`
+	DateModified JSONTime `json:"date_modified"`
+}
+
+// String representation in pretty JSON form
+func (self *Project) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Project as json string
+func (self *Project) AsJSON() string {
+	return asJSON(self)
+}
+
+// Project as pretty json
+func (self *Project) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Full API response for a single project
+type ProjectResponse struct {
+	BaseAPIResponse
+	*Project `json:"result"`
+}
+
+// Full API response for multiple projects
+type ProjectsResponse struct {
+	BaseAPIResponse
+	Projects []*Project `json:"result"`
+}
+
+// Projects response as a pretty json string
+func (self *ProjectsResponse) String() string {
+	return asPrettyJSON(self.Projects)
+}
+
+// Project access token object as returned from API
+type AccessToken struct {
+	ProjectID uint64             `json:"project_id"`
+	Token     string             `json:"access_token"`
+	Name      string             `json:"name"`
+	Status    string             `json:"status"`
+	Scopes    []AccessTokenScope `json:"scopes"`
+
+	// Max number of calls allowed per window. 0 is unlimited.
+	RateLimitWindowCount uint64 `json:"rate_limit_window_count"`
+	// Window size in seconds
+	RateLimitWindowSize uint64 `json:"rate_limit_window_size"`
+
+	DateCreated  JSONTime `json:"date_created"`
+	DateModified JSONTime `json:"date_modified"`
+}
+
+// String representation in pretty JSON form
+func (self *AccessToken) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Access token as json string
+func (self *AccessToken) AsJSON() string {
+	return asJSON(self)
+}
+
+// Access token as pretty json
+func (self *AccessToken) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Info used to create a project access token
+type AccessTokenRequest struct {
+	// Required
+	Name   string             `json:"name"`
+	Scopes []AccessTokenScope `json:"scopes"`
+
+	// Optional. "enabled" or "disabled"
+	Status string `json:"status,omitempty"`
+
+	// Optional rate limit: max number of calls per window of
+	// RateLimitWindowSize seconds
+	RateLimitWindowCount uint64 `json:"rate_limit_window_count,omitempty"`
+	RateLimitWindowSize  uint64 `json:"rate_limit_window_size,omitempty"`
+}
+
+// Full API response for a single access token
+type AccessTokenResponse struct {
+	BaseAPIResponse
+	*AccessToken `json:"result"`
+}
+
+// Full API response for multiple access tokens
+type AccessTokensResponse struct {
+	BaseAPIResponse
+	AccessTokens []*AccessToken `json:"result"`
+}
+
+// Access tokens response as a pretty json string
+func (self *AccessTokensResponse) String() string {
+	return asPrettyJSON(self.AccessTokens)
+}
+
+// Get all projects in the account
+func (self *client) GetProjects() (*ProjectsResponse, error) {
+	projects_resp := &ProjectsResponse{}
+
+	err := self.httpGet(scopeAccountRead, "/projects", nil, &projects_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects_resp, nil
+}
+
+// Get a single project by its id
+func (self *client) GetProject(id uint64) (*ProjectResponse, error) {
+	project_resp := &ProjectResponse{}
+
+	err := self.httpGet(
+		scopeAccountRead,
+		fmt.Sprintf("/project/%d", id),
+		nil,
+		&project_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return project_resp, nil
+}
+
+// Create a project
+func (self *client) CreateProject(name string) (*ProjectResponse, error) {
+	if name == "" {
+		return nil, errors.New("Project name is required")
+	}
+
+	project_resp := &ProjectResponse{}
+
+	err := self.httpPost(
+		scopeAccountWrite,
+		"/projects",
+		map[string]interface{}{
+			"name": name,
+		},
+		&project_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return project_resp, nil
+}
+
+// Delete a project by its id
+func (self *client) DeleteProject(id uint64) error {
+	delete_resp := &BaseAPIResponse{}
+
+	err := self.httpDelete(
+		scopeAccountWrite,
+		fmt.Sprintf("/project/%d", id),
+		&delete_resp,
+	)
+	return err
+}
+
+// Get all access tokens for a project
+func (self *client) GetProjectAccessTokens(project_id uint64) (*AccessTokensResponse, error) {
+	tokens_resp := &AccessTokensResponse{}
+
+	err := self.httpGet(
+		scopeAccountRead,
+		fmt.Sprintf("/project/%d/access_tokens", project_id),
+		nil,
+		&tokens_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens_resp, nil
+}
+
+// Create an access token for a project
+func (self *client) CreateProjectAccessToken(project_id uint64, token *AccessTokenRequest) (*AccessTokenResponse, error) {
+	if token.Name == "" || len(token.Scopes) == 0 {
+		return nil, errors.New("Access token name and scopes are required")
+	}
+
+	token_resp := &AccessTokenResponse{}
+
+	err := self.httpPost(
+		scopeAccountWrite,
+		fmt.Sprintf("/project/%d/access_tokens", project_id),
+		token,
+		&token_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return token_resp, nil
+}
@@ -0,0 +1,118 @@
+package rollbar
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestProjectCalls(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(c *client) error
+		response string
+		want     string
+	}{
+		{
+			"list projects",
+			func(c *client) error {
+				resp, err := c.GetProjects()
+				if err == nil && (len(resp.Projects) != 1 || resp.Projects[0].Name != "api") {
+					t.Errorf("Expected the project, got %v", resp.Projects)
+				}
+				return err
+			},
+			`{"err": 0, "result": [{"id": 1, "name": "api"}]}`,
+			"GET /projects ",
+		},
+		{
+			"get project",
+			func(c *client) error {
+				resp, err := c.GetProject(1)
+				if err == nil && (resp.Project == nil || resp.Name != "api") {
+					t.Errorf("Expected the project, got %v", resp.Project)
+				}
+				return err
+			},
+			`{"err": 0, "result": {"id": 1, "name": "api"}}`,
+			"GET /project/1 ",
+		},
+		{
+			"create project",
+			func(c *client) error {
+				_, err := c.CreateProject("api")
+				return err
+			},
+			`{"err": 0, "result": {"id": 1, "name": "api"}}`,
+			`POST /projects {"name":"api"}`,
+		},
+		{
+			"delete project",
+			func(c *client) error { return c.DeleteProject(1) },
+			`{"err": 0}`,
+			"DELETE /project/1 ",
+		},
+		{
+			"list tokens",
+			func(c *client) error {
+				resp, err := c.GetProjectAccessTokens(1)
+				if err == nil && (len(resp.AccessTokens) != 1 || resp.AccessTokens[0].Scopes[0] != SCOPE_READ) {
+					t.Errorf("Expected the token, got %v", resp.AccessTokens)
+				}
+				return err
+			},
+			`{"err": 0, "result": [{"project_id": 1, "name": "ci", "scopes": ["read"]}]}`,
+			"GET /project/1/access_tokens ",
+		},
+		{
+			"create token",
+			func(c *client) error {
+				_, err := c.CreateProjectAccessToken(1, &AccessTokenRequest{
+					Name:                 "ci",
+					Scopes:               []AccessTokenScope{SCOPE_READ, SCOPE_WRITE},
+					RateLimitWindowCount: 100,
+					RateLimitWindowSize:  60,
+				})
+				return err
+			},
+			`{"err": 0, "result": {"project_id": 1, "name": "ci"}}`,
+			`POST /project/1/access_tokens {"name":"ci","scopes":["read","write"],"rate_limit_window_count":100,"rate_limit_window_size":60}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				body, _ := io.ReadAll(req.Body)
+				path := strings.TrimPrefix(req.URL.Path, "/api/1")
+				got = req.Method + " " + path + " " + string(body)
+				w.Write([]byte(tt.response))
+			})
+
+			if err := tt.call(c); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProjectCallsRequireFields(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+	})
+
+	if _, err := c.CreateProject(""); err == nil {
+		t.Errorf("Expected an error without a project name")
+	}
+	if _, err := c.CreateProjectAccessToken(1, &AccessTokenRequest{Name: "ci"}); err == nil {
+		t.Errorf("Expected an error without scopes")
+	}
+	if _, err := c.CreateProjectAccessToken(1, &AccessTokenRequest{Scopes: []AccessTokenScope{SCOPE_READ}}); err == nil {
+		t.Errorf("Expected an error without a name")
+	}
+}
@@ -0,0 +1,187 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Filters for the reports API. Empty fields use rollbar's defaults.
+type ReportFilter struct {
+	Environments []string
+
+	// Hours of history for top active items (rollbar default is 24)
+	Hours uint64
+
+	// Size of each time bucket for counts (rollbar default is a day).
+	// Rounded down to whole seconds.
+	BucketSize time.Duration
+}
+
+func (self *ReportFilter) topItemsQuery() url.Values {
+	query := url.Values{}
+	if self == nil {
+		return query
+	}
+	if len(self.Environments) > 0 {
+		query.Set("environments", strings.Join(self.Environments, ","))
+	}
+	if self.Hours != 0 {
+		query.Set("hours", fmt.Sprintf("%d", self.Hours))
+	}
+	return query
+}
+
+func (self *ReportFilter) countsQuery() url.Values {
+	query := url.Values{}
+	if self == nil {
+		return query
+	}
+	for _, env := range self.Environments {
+		query.Add("environment", env)
+	}
+	if secs := int64(self.BucketSize / time.Second); secs > 0 {
+		query.Set("bucket_size", fmt.Sprintf("%d", secs))
+	}
+	return query
+}
+
+// Item summary as returned in top active items
+type TopActiveItemInfo struct {
+	ID                      uint64            `json:"id"`
+	Project_id              uint64            `json:"project_id"`
+	Counter                 uint64            `json:"counter"`
+	Environment             string            `json:"environment"`
+	Title                   string            `json:"title"`
+	Level                   NotificationLevel `json:"level"`
+	Occurrences             uint64            `json:"occurrences"`
+	UniqueOccurrences       uint64            `json:"unique_occurrences"`
+	LastOccurrenceTimestamp JSONTime          `json:"last_occurrence_timestamp"`
+	// Numeric framework id
+	Framework interface{} `json:"framework"`
+}
+
+// An item and its hourly occurrence counts, oldest first
+type TopActiveItem struct {
+	Item   *TopActiveItemInfo `json:"item"`
+	Counts []uint64           `json:"counts"`
+}
+
+// Top active item as json string
+func (self *TopActiveItem) AsJSON() string {
+	return asJSON(self)
+}
+
+// Full API response for top active items, most active first
+type TopActiveItemsResponse struct {
+	BaseAPIResponse
+	TopItems []*TopActiveItem `json:"result"`
+}
+
+// String representation of top active items response (pretty json)
+func (self *TopActiveItemsResponse) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Top active items response as a json string
+func (self *TopActiveItemsResponse) AsJSON() string {
+	return asJSON(self.TopItems)
+}
+
+// Top active items response as a pretty json string
+func (self *TopActiveItemsResponse) AsPrettyJSON() string {
+	return asPrettyJSON(self.TopItems)
+}
+
+// Count for a single time bucket. The API sends these as
+// [timestamp, count] pairs.
+type ReportCount struct {
+	Timestamp JSONTime
+	Count     uint64
+}
+
+func (self *ReportCount) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("Expected [timestamp, count], got: %s", string(data))
+	}
+	if err := json.Unmarshal(pair[0], &self.Timestamp); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &self.Count)
+}
+
+func (self ReportCount) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{self.Timestamp, self.Count})
+}
+
+// Time series of counts, oldest first
+type ReportCounts []*ReportCount
+
+// Sum of all counts
+func (self ReportCounts) Total() uint64 {
+	var total uint64
+	for _, count := range self {
+		total += count.Count
+	}
+	return total
+}
+
+// Full API response for a count time series
+type ReportCountsResponse struct {
+	BaseAPIResponse
+	Counts ReportCounts `json:"result"`
+}
+
+// String representation of counts response (pretty json)
+func (self *ReportCountsResponse) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Counts response as a json string
+func (self *ReportCountsResponse) AsJSON() string {
+	return asJSON(self.Counts)
+}
+
+// Counts response as a pretty json string
+func (self *ReportCountsResponse) AsPrettyJSON() string {
+	return asPrettyJSON(self.Counts)
+}
+
+// Get the most active items, with their recent hourly counts
+func (self *client) GetTopActiveItems(filter *ReportFilter) (*TopActiveItemsResponse, error) {
+	resp := &TopActiveItemsResponse{}
+
+	err := self.httpGet(scopeRead, "/reports/top_active_items", filter.topItemsQuery(), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get the number of occurrences over time
+func (self *client) GetOccurrenceCounts(filter *ReportFilter) (*ReportCountsResponse, error) {
+	return self.getReportCounts("/reports/occurrence_counts", filter)
+}
+
+// Get the number of items activated (new or reactivated) over time
+func (self *client) GetActivatedCounts(filter *ReportFilter) (*ReportCountsResponse, error) {
+	return self.getReportCounts("/reports/activated_counts", filter)
+}
+
+func (self *client) getReportCounts(url string, filter *ReportFilter) (*ReportCountsResponse, error) {
+	resp := &ReportCountsResponse{}
+
+	err := self.httpGet(scopeRead, url, filter.countsQuery(), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,109 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetTopActiveItems(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		query = req.URL.RawQuery
+		w.Write([]byte(`{"err": 0, "result": [
+			{"item": {"id": 1, "counter": 12, "title": "boom", "level": "error", "occurrences": 30, "last_occurrence_timestamp": 1700000000}, "counts": [10, 20]}
+		]}`))
+	})
+
+	resp, err := c.GetTopActiveItems(&ReportFilter{Environments: []string{"production", "staging"}, Hours: 48})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if want := "environments=production%2Cstaging&hours=48"; query != want {
+		t.Errorf("Expected query %s, got %s", want, query)
+	}
+	if len(resp.TopItems) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(resp.TopItems))
+	}
+	top := resp.TopItems[0]
+	if top.Item.Counter != 12 || top.Item.Level != LV_ERROR || len(top.Counts) != 2 {
+		t.Errorf("Unexpected item %s", top.AsJSON())
+	}
+	if ts := top.Item.LastOccurrenceTimestamp.Unix(); ts != 1700000000 {
+		t.Errorf("Expected the last occurrence timestamp, got %d", ts)
+	}
+}
+
+func TestGetReportCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		get    func(c *client, filter *ReportFilter) (*ReportCountsResponse, error)
+		filter *ReportFilter
+		path   string
+		query  string
+	}{
+		{"occurrences", (*client).GetOccurrenceCounts, nil, "/reports/occurrence_counts", ""},
+		{
+			"activated",
+			(*client).GetActivatedCounts,
+			&ReportFilter{Environments: []string{"production", "staging"}, BucketSize: 90 * time.Minute},
+			"/reports/activated_counts",
+			"bucket_size=5400&environment=production&environment=staging",
+		},
+		{
+			"sub-second bucket",
+			(*client).GetOccurrenceCounts,
+			&ReportFilter{BucketSize: time.Millisecond},
+			"/reports/occurrence_counts",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, query string
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				path, query = strings.TrimPrefix(req.URL.Path, "/api/1"), req.URL.RawQuery
+				w.Write([]byte(`{"err": 0, "result": [[1700000000, 3], [1700003600, 4]]}`))
+			})
+
+			resp, err := tt.get(c, tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if path != tt.path || query != tt.query {
+				t.Errorf("Expected %s?%s, got %s?%s", tt.path, tt.query, path, query)
+			}
+			if len(resp.Counts) != 2 || resp.Counts.Total() != 7 {
+				t.Errorf("Expected 2 counts totalling 7, got %s", resp.AsJSON())
+			}
+			if ts := resp.Counts[1].Timestamp.Unix(); ts != 1700003600 {
+				t.Errorf("Expected the bucket's timestamp, got %d", ts)
+			}
+		})
+	}
+}
+
+func TestReportCountJSON(t *testing.T) {
+	var count ReportCount
+	if err := json.Unmarshal([]byte(`[1700000000, 3]`), &count); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	data, err := json.Marshal(count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ReportCount
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error decoding %s: %s", data, err)
+	}
+	if decoded.Timestamp.Unix() != 1700000000 || decoded.Count != 3 {
+		t.Errorf("Expected the count to round trip, got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`[1700000000]`), &count); err == nil {
+		t.Errorf("Expected an error for a malformed pair")
+	}
+}
@@ -0,0 +1,249 @@
+package rollbar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	net_url "net/url"
+	"strings"
+)
+
+// Default maximum number of request body bytes captured
+const DEFAULT_MAX_BODY_SIZE = 64 * 1024
+
+// Options for NewNotifierRequest()
+type NotifierRequestOptions struct {
+	// Maximum number of body bytes to capture. Defaults to
+	// DEFAULT_MAX_BODY_SIZE. Use a negative value to skip the body.
+	MaxBodySize int64
+
+	// Proxies trusted to set X-Forwarded-For and X-Forwarded-Proto. See
+	// ParseTrustedProxies().
+	TrustedProxies []*net.IPNet
+
+	// Fields to scrub. Defaults to DefaultScrubFields
+	ScrubFields []string
+}
+
+// Parse a list of IPs and/or CIDRs for NotifierRequestOptions
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid trusted proxy: %s", proxy)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid trusted proxy: %s", err)
+		}
+		nets = append(nets, ipnet)
+	}
+	return nets, nil
+}
+
+// Create a NotifierRequest from an http.Request. The body is captured (up
+// to MaxBodySize) and parsed by content type: forms and JSON objects go in
+// POSTParams, anything else in Body. Form and JSON bodies that can't be
+// parsed or were truncated are left out, as they can't be scrubbed. Other
+// bodies (e.g. text/plain or XML) are sent as they are, unscrubbed; use a
+// negative MaxBodySize if they may hold secrets. req.Body is replaced so
+// that it can still be fully read by the handler. 'options' may be nil. A
+// non-nil error means the body could not be read; the returned request is
+// still usable.
+func NewNotifierRequest(req *http.Request, options *NotifierRequestOptions) (*NotifierRequest, error) {
+	if options == nil {
+		options = &NotifierRequestOptions{}
+	}
+
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	if isTrustedProxy(options.TrustedProxies, remoteIP(req)) {
+		if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+		}
+	}
+
+	notif_req := &NotifierRequest{
+		URL:         scheme + "://" + req.Host + req.URL.RequestURI(),
+		Method:      req.Method,
+		Headers:     make(map[string]string, len(req.Header)),
+		QueryString: req.URL.RawQuery,
+		UserIP:      userIP(options.TrustedProxies, req),
+	}
+
+	for k, v := range req.Header {
+		notif_req.Headers[k] = strings.Join(v, ", ")
+	}
+
+	if query := req.URL.Query(); len(query) != 0 {
+		notif_req.GETParams = make(map[string]string, len(query))
+		for k, v := range query {
+			notif_req.GETParams[k] = strings.Join(v, ",")
+		}
+	}
+
+	var err error
+
+	max_size := options.MaxBodySize
+	if max_size == 0 {
+		max_size = DEFAULT_MAX_BODY_SIZE
+	}
+	scrub_fields := options.ScrubFields
+	if scrub_fields == nil {
+		scrub_fields = DefaultScrubFields
+	}
+
+	if max_size > 0 && req.Body != nil && req.Body != http.NoBody {
+		err = notif_req.captureBody(req, max_size, scrub_fields)
+	}
+
+	notif_req.Scrub(scrub_fields)
+
+	return notif_req, err
+}
+
+// Body that replays what was captured before continuing with the original
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
+
+func (self *NotifierRequest) captureBody(req *http.Request, max_size int64, scrub_fields []string) error {
+	data, err := io.ReadAll(io.LimitReader(req.Body, max_size+1))
+	req.Body = &restoredBody{
+		Reader: io.MultiReader(bytes.NewReader(data), req.Body),
+		Closer: req.Body,
+	}
+	if err != nil {
+		return err
+	}
+
+	truncated := int64(len(data)) > max_size
+	if truncated {
+		data = data[:max_size]
+	}
+	self.parseBody(req.Header.Get("Content-Type"), data, truncated, scrub_fields)
+	return nil
+}
+
+// Set the body from data captured elsewhere (e.g. while the handler read
+// it), parsed as NewNotifierRequest() does and scrubbed with
+// 'scrub_fields' (nil for DefaultScrubFields). 'truncated' means 'data'
+// is only the start of the body.
+func (self *NotifierRequest) SetBody(content_type string, data []byte, truncated bool, scrub_fields []string) *NotifierRequest {
+	if scrub_fields == nil {
+		scrub_fields = DefaultScrubFields
+	}
+	self.parseBody(content_type, data, truncated, scrub_fields)
+	return self.Scrub(scrub_fields)
+}
+
+func (self *NotifierRequest) parseBody(content_type string, data []byte, truncated bool, scrub_fields []string) {
+	media_type, _, _ := mime.ParseMediaType(content_type)
+
+	// Form and JSON bodies can only be scrubbed once parsed, so they're
+	// left out if they can't be (e.g. they were truncated)
+	switch {
+	case media_type == "application/x-www-form-urlencoded":
+		if truncated {
+			return
+		}
+		if form, err := net_url.ParseQuery(string(data)); err == nil {
+			self.POSTParams = make(map[string]interface{}, len(form))
+			for k, v := range form {
+				if len(v) == 1 {
+					self.POSTParams[k] = v[0]
+				} else {
+					self.POSTParams[k] = v
+				}
+			}
+		}
+		return
+	case media_type == "application/json" || strings.HasSuffix(media_type, "+json"):
+		if truncated {
+			return
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return
+		}
+		if obj, ok := value.(map[string]interface{}); ok {
+			self.POSTParams = obj
+			return
+		}
+		// Anything else (e.g. an array) is scrubbed now and kept as the body
+		scrubNested(scrub_fields, value)
+		if data, err := json.Marshal(value); err == nil {
+			self.Body = string(data)
+		}
+		return
+	case strings.HasPrefix(media_type, "multipart/"):
+		// Could contain files. Skip it.
+		return
+	}
+
+	self.Body = string(data)
+}
+
+func isTrustedProxy(proxies []*net.IPNet, ip_str string) bool {
+	ip := net.ParseIP(ip_str)
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range proxies {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// Determine the user's IP. X-Forwarded-For is walked from the right,
+// skipping trusted proxies, and only if the remote address is trusted.
+func userIP(proxies []*net.IPNet, req *http.Request) string {
+	ip := remoteIP(req)
+	if !isTrustedProxy(proxies, ip) {
+		return ip
+	}
+
+	var hops []string
+	for _, hdr := range req.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(hdr, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip = hops[i]
+		if !isTrustedProxy(proxies, ip) {
+			break
+		}
+	}
+
+	return ip
+}
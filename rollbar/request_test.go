@@ -0,0 +1,66 @@
+package rollbar
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewNotifierRequestBody(t *testing.T) {
+	const secret = "hunter2"
+	big_form := "password=" + secret + "&padding=" + strings.Repeat("x", 100)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		maxSize     int64
+		wantPOST    map[string]interface{}
+		wantBody    string
+	}{
+		{"form", "application/x-www-form-urlencoded", "a=1&password=" + secret, 0, map[string]interface{}{"a": "1", "password": SCRUBBED_VALUE}, ""},
+		{"oversized form", "application/x-www-form-urlencoded", big_form, 50, nil, ""},
+		{"unparseable form", "application/x-www-form-urlencoded", "password=" + secret + "&%zz", 0, nil, ""},
+		{"json object", "application/json", `{"password": "` + secret + `"}`, 0, map[string]interface{}{"password": SCRUBBED_VALUE}, ""},
+		{"truncated json", "application/json", `{"password": "` + secret + `", "padding": "xxxxxxxxxx"}`, 30, nil, ""},
+		{"json array", "application/json", `[{"password": "` + secret + `"}]`, 0, nil, `[{"password":"` + SCRUBBED_VALUE + `"}]`},
+		{"invalid json", "application/vnd.api+json", `{"password": "` + secret + `"`, 0, nil, ""},
+		{"multipart", "multipart/form-data; boundary=x", "--x--", 0, nil, ""},
+		{"text", "text/plain", "hello", 0, nil, "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/submit", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			notif_req, err := NewNotifierRequest(req, &NotifierRequestOptions{MaxBodySize: tt.maxSize})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if notif_req.Body != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, notif_req.Body)
+			}
+			if len(notif_req.POSTParams) != len(tt.wantPOST) {
+				t.Errorf("Expected POST params %v, got %v", tt.wantPOST, notif_req.POSTParams)
+			}
+			for k, v := range tt.wantPOST {
+				if notif_req.POSTParams[k] != v {
+					t.Errorf("Expected POST param %s=%v, got %v", k, v, notif_req.POSTParams[k])
+				}
+			}
+
+			data, _ := notif_req.MarshalJSON()
+			if strings.Contains(string(data), secret) {
+				t.Errorf("Secret leaked: %s", data)
+			}
+
+			// The handler still gets the whole body
+			if body, _ := io.ReadAll(req.Body); string(body) != tt.body {
+				t.Errorf("Expected the handler to read %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
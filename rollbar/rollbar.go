@@ -1,6 +1,7 @@
 package rollbar
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -19,6 +20,10 @@ type ClientOptions struct {
 	// Logger to use
 	Logger *log.Logger
 
+	// Log every API request and response to Logger. Access tokens and
+	// values of ScrubFields in JSON bodies are redacted.
+	Debug bool
+
 	// The following affect the notifier
 
 	// Your environment. Defaults to "development"
@@ -28,27 +33,100 @@ type ClientOptions struct {
 	Platform       string
 	Language       string
 	Framework      string
+
+	// Field names (headers, params) whose values are scrubbed from
+	// request data. Defaults to DefaultScrubFields
+	ScrubFields []string
+
+	// Maximum number of telemetry entries kept and sent with each
+	// notification. Defaults to DEFAULT_MAX_TELEMETRY. Use a negative
+	// value to disable telemetry.
+	MaxTelemetry int
+
+	// Project access tokens by scope. Each call uses the token for the
+	// scope it needs, falling back to the token given to NewClient.
+	PostServerItemToken string
+	ReadToken           string
+	WriteToken          string
+
+	// Account access tokens, used for projects, teams and users
+	AccountReadToken  string
+	AccountWriteToken string
 }
 
-// Client interface
+// Client interface. Methods may be added to it, so implementations
+// outside this package (e.g. fakes for tests) should embed
+// NewNOOPClient() rather than implement every method.
 type Client interface {
 	APIBaseURL() string
 	SetAPIBaseURL(base_url string) Client
 	Options() *ClientOptions
 	GetItem(id uint64) (*ItemResponse, error)
 	GetItemByCounter(counter uint64) (*ItemResponse, error)
-	SetItemStatus(id uint64, status string) error
-	SetItemStatusByCounter(counter uint64, status string) error
+	SetItemStatus(id uint64, status ItemStatus) error
+	SetItemStatusByCounter(counter uint64, status ItemStatus) error
+	UpdateItem(id uint64, update *ItemUpdate) (*ItemResponse, error)
+	UpdateItemByCounter(counter uint64, update *ItemUpdate) (*ItemResponse, error)
+	ListItems(filter *ItemFilter) (*ItemsResponse, error)
+	ListItemsWithPage(filter *ItemFilter, page uint64) (*ItemsResponse, error)
+	// Deprecated: Use ItemsPager.
+	IterItems(filter *ItemFilter) *ItemIterator
+	ItemsPager(filter *ItemFilter, options *PagerOptions) *Pager[*Item]
 	GetItemOccurrences(item_id uint64) (*OccurrencesResponse, error)
 	GetItemOccurrencesWithPage(item_id uint64, page uint64) (*OccurrencesResponse, error)
+	ItemOccurrencesPager(item_id uint64, options *PagerOptions) *Pager[*Occurrence]
 	GetOccurrence(id uint64) (*OccurrenceResponse, error)
 	GetOccurrences() (*OccurrencesResponse, error)
 	GetOccurrencesWithPage(page uint64) (*OccurrencesResponse, error)
+	OccurrencesPager(options *PagerOptions) *Pager[*Occurrence]
+	GetTopActiveItems(filter *ReportFilter) (*TopActiveItemsResponse, error)
+	GetOccurrenceCounts(filter *ReportFilter) (*ReportCountsResponse, error)
+	GetActivatedCounts(filter *ReportFilter) (*ReportCountsResponse, error)
+	RecordDeploy(deploy *DeployRequest) (*RecordDeployResponse, error)
+	SetDeployStatus(id uint64, status DeployStatus) (*DeployResponse, error)
+	GetDeploy(id uint64) (*DeployResponse, error)
+	GetDeploys() (*DeploysResponse, error)
+	GetDeploysWithPage(page uint64) (*DeploysResponse, error)
+	DeploysPager(options *PagerOptions) *Pager[*Deploy]
+	GetProjects() (*ProjectsResponse, error)
+	GetProject(id uint64) (*ProjectResponse, error)
+	CreateProject(name string) (*ProjectResponse, error)
+	DeleteProject(id uint64) error
+	GetProjectAccessTokens(project_id uint64) (*AccessTokensResponse, error)
+	CreateProjectAccessToken(project_id uint64, token *AccessTokenRequest) (*AccessTokenResponse, error)
+	GetTeams() (*TeamsResponse, error)
+	GetTeam(id uint64) (*TeamResponse, error)
+	CreateTeam(name string, access_level TeamAccessLevel) (*TeamResponse, error)
+	DeleteTeam(id uint64) error
+	GetTeamUsers(team_id uint64) (*TeamUsersResponse, error)
+	GetTeamUsersWithPage(team_id uint64, page uint64) (*TeamUsersResponse, error)
+	TeamUsersPager(team_id uint64, options *PagerOptions) *Pager[*TeamUser]
+	AddTeamUser(team_id uint64, user_id uint64) error
+	RemoveTeamUser(team_id uint64, user_id uint64) error
+	GetTeamProjects(team_id uint64) (*TeamProjectsResponse, error)
+	AddTeamProject(team_id uint64, project_id uint64) error
+	RemoveTeamProject(team_id uint64, project_id uint64) error
+	InviteTeamUser(team_id uint64, email string) (*InviteResponse, error)
+	GetTeamInvitesWithPage(team_id uint64, page uint64) (*InvitesResponse, error)
+	TeamInvitesPager(team_id uint64, options *PagerOptions) *Pager[*Invite]
+	GetUsers() (*UsersResponse, error)
+	GetUser(id uint64) (*UserResponse, error)
+	CreateRQLJob(ctx context.Context, query string, force_refresh bool) (*RQLJobResponse, error)
+	GetRQLJob(ctx context.Context, id uint64) (*RQLJobResponse, error)
+	GetRQLJobResult(ctx context.Context, id uint64) (*RQLJobResultResponse, error)
+	CancelRQLJob(ctx context.Context, id uint64) (*RQLJobResponse, error)
+	RunRQLQuery(ctx context.Context, query string, options *RQLOptions) (*RQLJobResult, error)
 	NewMessageNotification(level NotificationLevel, message string, custom CustomInfo) *MessageNotification
 	NewTraceNotification(level NotificationLevel, message string, custom CustomInfo) *TraceNotification
 	NewTraceChainNotification(level NotificationLevel, message string, custom CustomInfo) *TraceChainNotification
 	NewCrashReportNotification(level NotificationLevel, message string, custom CustomInfo) *CrashReportNotification
+	NewMessageNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *MessageNotification
+	NewTraceNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *TraceNotification
+	NewTraceChainNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *TraceChainNotification
+	NewCrashReportNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *CrashReportNotification
 	SendNotification(notif Notification) (*NotificationResponse, error)
+	WatchOccurrences(ctx context.Context, filter *WatchFilter) <-chan *WatchEvent
+	AddTelemetry(entry *NotifierTelemetry)
 }
 
 var DefaultClientOptions ClientOptions
@@ -61,13 +139,16 @@ func init() {
 		NotifierServer: NotifierServer{
 			Host: hostname,
 		},
-		Platform: runtime.GOOS,
-		Language: "go",
-		Logger:   log.New(os.Stderr, "", log.LstdFlags|log.Lmicroseconds),
+		Platform:    runtime.GOOS,
+		Language:    "go",
+		Logger:      log.New(os.Stderr, "", log.LstdFlags|log.Lmicroseconds),
+		ScrubFields: DefaultScrubFields,
 	}
 }
 
-// Create a new client with specified access token
+// Create a new client with specified access token. The token is used for
+// every call unless a token for the call's scope is set in the options.
+// It may be empty if only scoped tokens are used.
 func NewClient(access_token string) (Client, error) {
 	return &client{
 		httpClient:      &http.Client{},
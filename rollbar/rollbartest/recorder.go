@@ -0,0 +1,389 @@
+package rollbartest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Environment variable that makes AssertGolden() write golden files
+// instead of comparing against them
+const UPDATE_GOLDEN_ENV = "ROLLBARTEST_UPDATE_GOLDEN"
+
+// Time WaitReported() waits by default
+const DEFAULT_WAIT_TIMEOUT = 5 * time.Second
+
+// Which notifications to find. Empty fields are not filtered on.
+type NotificationFilter struct {
+	Level rollbar.NotificationLevel
+	Title string
+
+	// Matches trace notifications with this exception class anywhere in
+	// their trace chain
+	ExceptionClass string
+
+	// Matches notifications with this custom key, whatever its value
+	CustomKey string
+
+	// Matches notifications whose custom data has all of these values
+	Custom rollbar.CustomInfo
+}
+
+func (self *NotificationFilter) matches(notif rollbar.Notification) bool {
+	if self == nil {
+		return true
+	}
+	if self.Level != "" && notif.GetLevel() != self.Level {
+		return false
+	}
+	if self.Title != "" && notif.GetTitle() != self.Title {
+		return false
+	}
+	if self.ExceptionClass != "" {
+		found := false
+		for _, class := range ExceptionClasses(notif) {
+			if class == self.ExceptionClass {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	custom := notif.GetCustom()
+	if self.CustomKey != "" {
+		if _, ok := custom[self.CustomKey]; !ok {
+			return false
+		}
+	}
+	for k, v := range self.Custom {
+		actual, ok := custom[k]
+		if !ok || !reflect.DeepEqual(actual, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *NotificationFilter) String() string {
+	if self == nil {
+		return "any notification"
+	}
+	var parts []string
+	if self.Level != "" {
+		parts = append(parts, fmt.Sprintf("level=%s", self.Level))
+	}
+	if self.Title != "" {
+		parts = append(parts, fmt.Sprintf("title=%q", self.Title))
+	}
+	if self.ExceptionClass != "" {
+		parts = append(parts, fmt.Sprintf("exception=%s", self.ExceptionClass))
+	}
+	if self.CustomKey != "" {
+		parts = append(parts, fmt.Sprintf("custom key=%s", self.CustomKey))
+	}
+	if len(self.Custom) != 0 {
+		parts = append(parts, fmt.Sprintf("custom=%v", self.Custom))
+	}
+	if len(parts) == 0 {
+		return "any notification"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Exception classes of a trace or trace chain notification, outermost
+// first. Other notifications have none.
+func ExceptionClasses(notif rollbar.Notification) []string {
+	var traces []*rollbar.NotifierTrace
+	switch n := notif.(type) {
+	case *rollbar.TraceNotification:
+		traces = []*rollbar.NotifierTrace{&n.Trace}
+	case *rollbar.TraceChainNotification:
+		traces = n.TraceChain
+	}
+
+	var classes []string
+	for _, trace := range traces {
+		if trace != nil && trace.Exception != nil {
+			classes = append(classes, trace.Exception.Class)
+		}
+	}
+	return classes
+}
+
+// Client that records notifications instead of sending them. Other calls
+// behave like rollbar.NewNOOPClient()'s. Safe for concurrent use.
+//
+// Notifications sent with rollbar.SendNotificationAsync(), as the
+// integrations do, are recorded before it returns, so they can be
+// asserted on as soon as the code under test returns. Use WaitReported()
+// for notifications sent from other goroutines.
+//
+//	recorder := rollbartest.NewRecorder()
+//	doSomething(recorder)
+//	recorder.AssertReported(t, &rollbartest.NotificationFilter{Level: rollbar.LV_ERROR})
+type Recorder struct {
+	rollbar.Client
+
+	mu            sync.Mutex
+	notifications []rollbar.Notification
+
+	// Closed and replaced whenever a notification is recorded
+	recorded chan struct{}
+}
+
+// Create a new recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		Client:   rollbar.NewNOOPClient(),
+		recorded: make(chan struct{}),
+	}
+}
+
+// Set the base API URL. Returns the recorder so that chained calls keep
+// recording.
+func (self *Recorder) SetAPIBaseURL(base_url string) rollbar.Client {
+	self.Client.SetAPIBaseURL(base_url)
+	return self
+}
+
+// Record a notification. Its UUID is returned as if it had been sent,
+// generating one if it has none.
+func (self *Recorder) SendNotification(notif rollbar.Notification) (*rollbar.NotificationResponse, error) {
+	if notif.GetUUID() == "" {
+		notif.SetUUID(rollbar.NewUUID())
+	}
+
+	self.mu.Lock()
+	self.notifications = append(self.notifications, notif)
+	close(self.recorded)
+	self.recorded = make(chan struct{})
+	self.mu.Unlock()
+
+	resp := &rollbar.NotificationResponse{}
+	resp.Result.UUID = notif.GetUUID()
+	return resp, nil
+}
+
+// Record a notification right away instead of in the background
+func (self *Recorder) SendNotificationAsync(notif rollbar.Notification) {
+	self.SendNotification(notif)
+}
+
+// Forget all recorded notifications
+func (self *Recorder) Reset() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.notifications = nil
+}
+
+// Get all recorded notifications, oldest first. They're the same objects
+// that were sent, so don't modify them.
+func (self *Recorder) Notifications() []rollbar.Notification {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return append([]rollbar.Notification(nil), self.notifications...)
+}
+
+// Get recorded notifications matching 'filter' (which may be nil)
+func (self *Recorder) Find(filter *NotificationFilter) []rollbar.Notification {
+	var found []rollbar.Notification
+	for _, notif := range self.Notifications() {
+		if filter.matches(notif) {
+			found = append(found, notif)
+		}
+	}
+	return found
+}
+
+// Get recorded notifications with a level
+func (self *Recorder) ByLevel(level rollbar.NotificationLevel) []rollbar.Notification {
+	return self.Find(&NotificationFilter{Level: level})
+}
+
+// Get recorded trace notifications with an exception class
+func (self *Recorder) ByExceptionClass(class string) []rollbar.Notification {
+	return self.Find(&NotificationFilter{ExceptionClass: class})
+}
+
+// Get recorded notifications that have a custom key
+func (self *Recorder) ByCustomKey(key string) []rollbar.Notification {
+	return self.Find(&NotificationFilter{CustomKey: key})
+}
+
+// Fail the test unless a notification matching 'filter' was recorded.
+// Returns the matching notifications.
+func (self *Recorder) AssertReported(t testing.TB, filter *NotificationFilter) []rollbar.Notification {
+	t.Helper()
+	found := self.Find(filter)
+	if len(found) == 0 {
+		t.Errorf("Expected %s to be reported, got %s", filter, self.summary())
+	}
+	return found
+}
+
+// Wait for a notification matching 'filter' to be recorded, failing the
+// test if none is within 'timeout' (DEFAULT_WAIT_TIMEOUT if 0). Returns
+// the matching notifications.
+func (self *Recorder) WaitReported(t testing.TB, filter *NotificationFilter, timeout time.Duration) []rollbar.Notification {
+	t.Helper()
+	if timeout <= 0 {
+		timeout = DEFAULT_WAIT_TIMEOUT
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		self.mu.Lock()
+		recorded := self.recorded
+		self.mu.Unlock()
+
+		if found := self.Find(filter); len(found) != 0 {
+			return found
+		}
+
+		select {
+		case <-recorded:
+		case <-timer.C:
+			t.Errorf("Expected %s to be reported within %s, got %s", filter, timeout, self.summary())
+			return nil
+		}
+	}
+}
+
+// Fail the test if a notification matching 'filter' was recorded
+func (self *Recorder) AssertNotReported(t testing.TB, filter *NotificationFilter) {
+	t.Helper()
+	if found := self.Find(filter); len(found) != 0 {
+		t.Errorf("Expected %s not to be reported, got %s", filter, summarize(found))
+	}
+}
+
+// Stop the test unless exactly one trace notification was recorded, and
+// return it. Other kinds of notification are ignored.
+func (self *Recorder) RequireSingleTrace(t testing.TB) *rollbar.TraceNotification {
+	t.Helper()
+	var traces []*rollbar.TraceNotification
+	for _, notif := range self.Notifications() {
+		if trace, ok := notif.(*rollbar.TraceNotification); ok {
+			traces = append(traces, trace)
+		}
+	}
+	if len(traces) != 1 {
+		t.Fatalf("Expected a single trace to be reported, got %d: %s", len(traces), self.summary())
+	}
+	return traces[0]
+}
+
+// Recorded notifications as pretty JSON, as they would be posted.
+// Timestamps, UUIDs, stack frames and telemetry are left out, as they
+// change from run to run.
+func (self *Recorder) Snapshot() ([]byte, error) {
+	notifs := self.Notifications()
+	snapshot := make([]interface{}, len(notifs))
+	for i, notif := range notifs {
+		data, err := json.Marshal(notif)
+		if err != nil {
+			return nil, err
+		}
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		stripVolatile(obj)
+		snapshot[i] = obj
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Fail the test unless Snapshot() matches the golden file at 'path'. With
+// UPDATE_GOLDEN_ENV set, the file is written instead.
+func (self *Recorder) AssertGolden(t testing.TB, path string) {
+	t.Helper()
+
+	actual, err := self.Snapshot()
+	if err != nil {
+		t.Fatalf("Error taking snapshot: %s", err)
+	}
+
+	if os.Getenv(UPDATE_GOLDEN_ENV) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Error creating golden file directory: %s", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("Error writing golden file: %s", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading golden file (set %s=1 to create it): %s", UPDATE_GOLDEN_ENV, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf(
+			"Notifications don't match %s (set %s=1 to update it)\n--- expected\n%s\n--- actual\n%s",
+			path,
+			UPDATE_GOLDEN_ENV,
+			expected,
+			actual,
+		)
+	}
+}
+
+func stripVolatile(obj map[string]interface{}) {
+	delete(obj, "timestamp")
+	delete(obj, "uuid")
+
+	body, _ := obj["body"].(map[string]interface{})
+	if body == nil {
+		return
+	}
+	delete(body, "telemetry")
+	if trace, ok := body["trace"].(map[string]interface{}); ok {
+		delete(trace, "frames")
+	}
+	if chain, ok := body["trace_chain"].([]interface{}); ok {
+		for _, t := range chain {
+			if trace, ok := t.(map[string]interface{}); ok {
+				delete(trace, "frames")
+			}
+		}
+	}
+}
+
+func (self *Recorder) summary() string {
+	return summarize(self.Notifications())
+}
+
+// Short description of notifications for failure messages
+func summarize(notifs []rollbar.Notification) string {
+	if len(notifs) == 0 {
+		return "no notifications"
+	}
+	lines := make([]string, len(notifs))
+	for i, notif := range notifs {
+		line := fmt.Sprintf("  %s %q", notif.GetLevel(), notif.GetTitle())
+		if classes := ExceptionClasses(notif); len(classes) != 0 {
+			line += " (" + strings.Join(classes, ", caused by ") + ")"
+		}
+		lines[i] = line
+	}
+	return fmt.Sprintf("%d notifications:\n%s", len(notifs), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,242 @@
+package rollbartest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	rollbarhttp "github.com/comstud/go-rollbar/rollbar/http"
+)
+
+// testing.TB that records failures instead of failing the real test
+type fakeTB struct {
+	testing.TB
+	failed bool
+	fatal  bool
+}
+
+func (self *fakeTB) Helper() {}
+
+func (self *fakeTB) Errorf(format string, args ...interface{}) {
+	self.failed = true
+}
+
+func (self *fakeTB) Fatalf(format string, args ...interface{}) {
+	self.failed = true
+	self.fatal = true
+	runtime.Goexit()
+}
+
+// Run 'fn' with a fakeTB in its own goroutine, so that Fatalf can stop it
+func runFake(t *testing.T, fn func(tb testing.TB)) *fakeTB {
+	tb := &fakeTB{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(tb)
+	}()
+	<-done
+	return tb
+}
+
+type testError struct{}
+
+func (testError) Error() string { return "test error" }
+
+func newTestRecorder() *Recorder {
+	recorder := NewRecorder()
+	recorder.SendNotification(recorder.NewMessageNotification(
+		rollbar.LV_INFO,
+		"user logged in",
+		rollbar.CustomInfo{"user": "bob", "attempts": 2},
+	))
+
+	notif := recorder.NewTraceNotification(rollbar.LV_ERROR, "save failed", nil)
+	notif.Trace.AddExceptionFromError(testError{})
+	notif.Trace.AddRuntimeFrames(nil)
+	recorder.SendNotification(notif)
+
+	chain := recorder.NewTraceChainNotification(rollbar.LV_CRITICAL, "request failed", rollbar.CustomInfo{"user": "alice"})
+	chain.TraceChain = []*rollbar.NotifierTrace{
+		{Exception: &rollbar.NotifierException{Class: "http.Error", Message: "500"}},
+		{Exception: &rollbar.NotifierException{Class: "sql.ErrNoRows"}},
+	}
+	recorder.SendNotification(chain)
+
+	return recorder
+}
+
+func TestRecorderFind(t *testing.T) {
+	recorder := newTestRecorder()
+
+	tests := []struct {
+		name   string
+		filter *NotificationFilter
+		want   []string
+	}{
+		{"nil", nil, []string{"user logged in", "save failed", "request failed"}},
+		{"level", &NotificationFilter{Level: rollbar.LV_ERROR}, []string{"save failed"}},
+		{"title", &NotificationFilter{Title: "request failed"}, []string{"request failed"}},
+		{"exception", &NotificationFilter{ExceptionClass: "rollbartest.testError"}, []string{"save failed"}},
+		{"chained exception", &NotificationFilter{ExceptionClass: "sql.ErrNoRows"}, []string{"request failed"}},
+		{"custom key", &NotificationFilter{CustomKey: "user"}, []string{"user logged in", "request failed"}},
+		{"custom value", &NotificationFilter{Custom: rollbar.CustomInfo{"user": "bob", "attempts": 2}}, []string{"user logged in"}},
+		{"custom mismatch", &NotificationFilter{Custom: rollbar.CustomInfo{"attempts": 3}}, nil},
+		{"combined", &NotificationFilter{Level: rollbar.LV_INFO, CustomKey: "missing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, notif := range recorder.Find(tt.filter) {
+				got = append(got, notif.GetTitle())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if n := len(recorder.ByLevel(rollbar.LV_CRITICAL)); n != 1 {
+		t.Errorf("ByLevel: expected 1, got %d", n)
+	}
+	if n := len(recorder.ByExceptionClass("http.Error")); n != 1 {
+		t.Errorf("ByExceptionClass: expected 1, got %d", n)
+	}
+	if n := len(recorder.ByCustomKey("attempts")); n != 1 {
+		t.Errorf("ByCustomKey: expected 1, got %d", n)
+	}
+}
+
+func TestRecorderAssertions(t *testing.T) {
+	recorder := newTestRecorder()
+
+	tests := []struct {
+		name      string
+		fn        func(tb testing.TB)
+		wantFail  bool
+		wantFatal bool
+	}{
+		{"reported", func(tb testing.TB) {
+			recorder.AssertReported(tb, &NotificationFilter{Level: rollbar.LV_ERROR})
+		}, false, false},
+		{"not reported", func(tb testing.TB) {
+			recorder.AssertReported(tb, &NotificationFilter{Level: rollbar.LV_DEBUG})
+		}, true, false},
+		{"assert not reported", func(tb testing.TB) {
+			recorder.AssertNotReported(tb, &NotificationFilter{Level: rollbar.LV_DEBUG})
+		}, false, false},
+		{"assert not reported fails", func(tb testing.TB) {
+			recorder.AssertNotReported(tb, &NotificationFilter{CustomKey: "user"})
+		}, true, false},
+		{"single trace", func(tb testing.TB) {
+			if trace := recorder.RequireSingleTrace(tb); trace.GetTitle() != "save failed" {
+				tb.Errorf("Wrong trace")
+			}
+		}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := runFake(t, tt.fn)
+			if tb.failed != tt.wantFail || tb.fatal != tt.wantFatal {
+				t.Errorf("Expected failed=%v fatal=%v, got failed=%v fatal=%v", tt.wantFail, tt.wantFatal, tb.failed, tb.fatal)
+			}
+		})
+	}
+
+	recorder.Reset()
+	if n := len(recorder.Notifications()); n != 0 {
+		t.Fatalf("Expected no notifications after Reset, got %d", n)
+	}
+	tb := runFake(t, func(tb testing.TB) { recorder.RequireSingleTrace(tb) })
+	if !tb.fatal {
+		t.Errorf("Expected RequireSingleTrace to stop the test with no traces")
+	}
+}
+
+func TestRecorderRecordsIntegrationsSynchronously(t *testing.T) {
+	recorder := NewRecorder()
+	middleware, err := rollbarhttp.New(recorder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := middleware.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	})
+
+	for i := 0; i < 50; i++ {
+		recorder.Reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/broken", nil))
+		if n := len(recorder.Notifications()); n != 1 {
+			t.Fatalf("Run %d: expected 1 notification right after ServeHTTP, got %d", i, n)
+		}
+	}
+}
+
+func TestRecorderWaitReported(t *testing.T) {
+	recorder := NewRecorder()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		recorder.SendNotification(recorder.NewMessageNotification(rollbar.LV_INFO, "unrelated", nil))
+		time.Sleep(10 * time.Millisecond)
+		recorder.SendNotification(recorder.NewMessageNotification(rollbar.LV_WARNING, "later", nil))
+	}()
+
+	found := recorder.WaitReported(t, &NotificationFilter{Level: rollbar.LV_WARNING}, time.Second)
+	if len(found) != 1 || found[0].GetTitle() != "later" {
+		t.Errorf("Expected the later notification, got %v", found)
+	}
+
+	tb := runFake(t, func(tb testing.TB) {
+		recorder.WaitReported(tb, &NotificationFilter{Level: rollbar.LV_DEBUG}, 20*time.Millisecond)
+	})
+	if !tb.failed {
+		t.Errorf("Expected WaitReported to fail after the timeout")
+	}
+}
+
+func TestRecorderSendNotificationUUID(t *testing.T) {
+	recorder := NewRecorder()
+
+	resp, err := recorder.SendNotification(recorder.NewMessageNotification(rollbar.LV_INFO, "x", nil))
+	if err != nil || resp.Result.UUID == "" {
+		t.Errorf("Expected a generated UUID, got %q (%v)", resp.Result.UUID, err)
+	}
+
+	notif := recorder.NewMessageNotification(rollbar.LV_INFO, "x", nil)
+	notif.SetUUID("my-uuid")
+	if resp, _ := recorder.SendNotification(notif); resp.Result.UUID != "my-uuid" {
+		t.Errorf("Expected the notification's UUID, got %q", resp.Result.UUID)
+	}
+}
+
+func TestRecorderGolden(t *testing.T) {
+	newTestRecorder().AssertGolden(t, filepath.Join("testdata", "recorder.golden.json"))
+}
+
+func TestRecorderGoldenMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	recorder := newTestRecorder()
+
+	t.Setenv(UPDATE_GOLDEN_ENV, "1")
+	recorder.AssertGolden(t, path)
+	t.Setenv(UPDATE_GOLDEN_ENV, "")
+	recorder.AssertGolden(t, path)
+
+	recorder.SendNotification(recorder.NewMessageNotification(rollbar.LV_INFO, "extra", nil))
+	tb := runFake(t, func(tb testing.TB) { recorder.AssertGolden(tb, path) })
+	if !tb.failed {
+		t.Errorf("Expected a changed snapshot not to match")
+	}
+
+	if tb.fatal {
+		t.Errorf("A mismatch shouldn't stop the test")
+	}
+}
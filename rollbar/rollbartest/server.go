@@ -0,0 +1,597 @@
+// Package rollbartest provides an in-memory fake of the rollbar API for
+// testing code that uses rollbar.Client without the network.
+//
+//	server := rollbartest.NewServer()
+//	defer server.Close()
+//
+//	client := server.NewClient()
+//	client.SendNotification(client.NewMessageNotification(rollbar.LV_ERROR, "oops", nil))
+//
+//	items := server.Items()
+package rollbartest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Access token used by NewClient()
+const DEFAULT_ACCESS_TOKEN = "rollbartest-token"
+
+// Occurrences per page, as with the real API
+const DEFAULT_PAGE_SIZE = 20
+
+// A failure to inject. Requests matching Method and Path get an error
+// response instead of being handled. Empty fields match everything.
+type Failure struct {
+	Method string
+
+	// Matches request paths starting with this, e.g. "/item/"
+	Path string
+
+	// HTTP status to return. Defaults to 500.
+	StatusCode int
+
+	// Message to return. Defaults to the status text.
+	Message string
+
+	// Fail this many matching requests, then stop. 0 means until
+	// ClearFailures() is called.
+	Times int
+}
+
+func (self *Failure) matches(req *http.Request) bool {
+	if self.Method != "" && !strings.EqualFold(self.Method, req.Method) {
+		return false
+	}
+	return strings.HasPrefix(req.URL.Path, self.Path)
+}
+
+// A request the server received
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Token  string
+	Body   []byte
+}
+
+// Fake rollbar API server. Posted items are grouped into Items by
+// environment and fingerprint, falling back to the title.
+type Server struct {
+	*httptest.Server
+
+	// Set these before making requests
+
+	// Only accept this token. Any non-empty token is accepted if not set.
+	AccessToken string
+
+	ProjectID uint64
+	PageSize  int
+
+	mu          sync.Mutex
+	items       []*rollbar.Item
+	itemsByKey  map[string]*rollbar.Item
+	occurrences []*occurrence
+	requests    []*Request
+	failures    []*Failure
+	latency     time.Duration
+	nextItemID  uint64
+	nextOccurID uint64
+}
+
+type occurrence struct {
+	itemID uint64
+	*rollbar.Occurrence
+}
+
+// Start a new server. Close() it when done.
+func NewServer() *Server {
+	self := &Server{
+		ProjectID: 1,
+		PageSize:  DEFAULT_PAGE_SIZE,
+	}
+	self.reset()
+	self.Server = httptest.NewServer(http.HandlerFunc(self.serveHTTP))
+	return self
+}
+
+// Create a client pointed at the server, using AccessToken or
+// DEFAULT_ACCESS_TOKEN
+func (self *Server) NewClient() rollbar.Client {
+	token := self.AccessToken
+	if token == "" {
+		token = DEFAULT_ACCESS_TOKEN
+	}
+	client, _ := rollbar.NewClient(token)
+	return client.SetAPIBaseURL(self.URL)
+}
+
+// Forget all items, occurrences, requests and failures. Latency is kept.
+func (self *Server) Reset() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.reset()
+}
+
+func (self *Server) reset() {
+	self.items = nil
+	self.itemsByKey = map[string]*rollbar.Item{}
+	self.occurrences = nil
+	self.requests = nil
+	self.failures = nil
+	self.nextItemID = 1000
+	self.nextOccurID = 100000
+}
+
+// Delay every response by 'latency'
+func (self *Server) SetLatency(latency time.Duration) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.latency = latency
+}
+
+// Inject a failure. Failures are checked in the order they were added.
+func (self *Server) Fail(failure *Failure) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	copied := *failure
+	self.failures = append(self.failures, &copied)
+}
+
+// Fail the next 'times' requests, whatever they are, with 'status_code'
+func (self *Server) FailNext(times int, status_code int) {
+	self.Fail(&Failure{StatusCode: status_code, Times: times})
+}
+
+// Remove all injected failures
+func (self *Server) ClearFailures() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.failures = nil
+}
+
+// Get copies of all items, oldest first
+func (self *Server) Items() []*rollbar.Item {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	items := make([]*rollbar.Item, len(self.items))
+	for i, item := range self.items {
+		copied := *item
+		items[i] = &copied
+	}
+	return items
+}
+
+// Get a copy of an item by its counter, or nil if there is none
+func (self *Server) ItemByCounter(counter uint64) *rollbar.Item {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	item := self.itemByCounter(counter)
+	if item == nil {
+		return nil
+	}
+	copied := *item
+	return &copied
+}
+
+// Get all occurrences, oldest first. They're shared with the server, so
+// don't modify them.
+func (self *Server) Occurrences() []*rollbar.Occurrence {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	occurs := make([]*rollbar.Occurrence, len(self.occurrences))
+	for i, occur := range self.occurrences {
+		occurs[i] = occur.Occurrence
+	}
+	return occurs
+}
+
+// Get all requests received, oldest first
+func (self *Server) Requests() []*Request {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return append([]*Request(nil), self.requests...)
+}
+
+func (self *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Error reading body: %s", err))
+		return
+	}
+
+	token := req.Header.Get(rollbar.ACCESS_TOKEN_HEADER)
+
+	self.mu.Lock()
+	self.requests = append(self.requests, &Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Token:  token,
+		Body:   body,
+	})
+	latency := self.latency
+	failure := self.takeFailure(req)
+	self.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	if failure != nil {
+		status := failure.StatusCode
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		msg := failure.Message
+		if msg == "" {
+			msg = http.StatusText(status)
+		}
+		writeError(w, status, msg)
+		return
+	}
+
+	if token == "" || (self.AccessToken != "" && token != self.AccessToken) {
+		writeError(w, http.StatusUnauthorized, "Invalid access token")
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.route(w, req, body)
+}
+
+// Get the failure for a request, if any, using up one of its times.
+// Must be called with the lock held.
+func (self *Server) takeFailure(req *http.Request) *Failure {
+	for i, failure := range self.failures {
+		if !failure.matches(req) {
+			continue
+		}
+		if failure.Times > 0 {
+			if failure.Times--; failure.Times == 0 {
+				self.failures = append(self.failures[:i], self.failures[i+1:]...)
+			}
+		}
+		return failure
+	}
+	return nil
+}
+
+func (self *Server) route(w http.ResponseWriter, req *http.Request, body []byte) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	switch {
+	case req.URL.Path == "/item/" || req.URL.Path == "/item":
+		if req.Method == http.MethodPost {
+			self.postItem(w, body)
+			return
+		}
+	case len(parts) == 2 && parts[0] == "item":
+		id, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			break
+		}
+		switch req.Method {
+		case http.MethodGet:
+			self.getItem(w, self.itemByID(id))
+			return
+		case http.MethodPatch:
+			self.patchItem(w, self.itemByID(id), body)
+			return
+		}
+	case len(parts) == 3 && parts[0] == "item" && parts[2] == "instances":
+		id, err := strconv.ParseUint(parts[1], 10, 64)
+		if err == nil && req.Method == http.MethodGet {
+			self.getOccurrences(w, req, id)
+			return
+		}
+	case len(parts) == 2 && parts[0] == "item_by_counter":
+		counter, err := strconv.ParseUint(parts[1], 10, 64)
+		if err == nil && req.Method == http.MethodGet {
+			self.getItem(w, self.itemByCounter(counter))
+			return
+		}
+	case req.URL.Path == "/instances":
+		if req.Method == http.MethodGet {
+			self.getOccurrences(w, req, 0)
+			return
+		}
+	case len(parts) == 2 && parts[0] == "instance":
+		id, err := strconv.ParseUint(parts[1], 10, 64)
+		if err == nil && req.Method == http.MethodGet {
+			self.getOccurrence(w, id)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not found")
+}
+
+func (self *Server) postItem(w http.ResponseWriter, body []byte) {
+	var payload struct {
+		Data *rollbar.OccurrenceData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
+		return
+	}
+	if payload.Data == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Missing 'data'")
+		return
+	}
+
+	data := payload.Data
+	if data.Body.Kind() == rollbar.BODY_UNKNOWN {
+		writeError(w, http.StatusUnprocessableEntity, "Missing or invalid 'body'")
+		return
+	}
+	if data.Level == "" {
+		data.Level = string(rollbar.LV_ERROR)
+	}
+	if data.UUID == "" {
+		data.UUID = rollbar.NewUUID()
+	}
+
+	now := time.Now()
+	if data.Timestamp.IsZero() {
+		data.Timestamp = rollbar.JSONTime{Time: now}
+	}
+
+	self.nextOccurID++
+	occur := &rollbar.Occurrence{
+		ID:         self.nextOccurID,
+		Project_id: self.ProjectID,
+		Timestamp:  rollbar.JSONTime{Time: now},
+		Version:    2,
+		Data:       *data,
+		Billable:   1,
+	}
+
+	item := self.itemFor(occur)
+	self.occurrences = append(self.occurrences, &occurrence{
+		itemID:     item.ID,
+		Occurrence: occur,
+	})
+
+	writeResult(w, map[string]interface{}{
+		"id":   nil,
+		"uuid": data.UUID,
+	})
+}
+
+// Find or create the item an occurrence belongs to, and update it.
+// Resolved items are reactivated.
+func (self *Server) itemFor(occur *rollbar.Occurrence) *rollbar.Item {
+	data := &occur.Data
+	title := itemTitle(data)
+
+	key := data.Fingerprint
+	if key == "" {
+		key = title
+	}
+	key = data.Environment + "\x00" + key
+
+	item := self.itemsByKey[key]
+	if item == nil {
+		self.nextItemID++
+		hash := sha1.Sum([]byte(key))
+		item = &rollbar.Item{
+			ID:                      self.nextItemID,
+			Project_id:              self.ProjectID,
+			Counter:                 uint64(len(self.items) + 1),
+			Environment:             data.Environment,
+			Platform:                data.Platform,
+			Framework:               data.Framework,
+			Hash:                    hex.EncodeToString(hash[:]),
+			Title:                   title,
+			FirstOccurrenceId:       occur.ID,
+			FirstOccurenceTimestamp: occur.Timestamp,
+			ActivatingOccurrenceId:  occur.ID,
+			LastActivatedTimestamp:  occur.Timestamp,
+			Status:                  rollbar.ITEM_ACTIVE,
+		}
+		self.items = append(self.items, item)
+		self.itemsByKey[key] = item
+	} else if item.Status == rollbar.ITEM_RESOLVED {
+		item.Status = rollbar.ITEM_ACTIVE
+		item.ActivatingOccurrenceId = occur.ID
+		item.LastActivatedTimestamp = occur.Timestamp
+	}
+
+	item.Level = data.Level
+	item.LastOccurrenceId = occur.ID
+	item.LastOccurenceTimestamp = occur.Timestamp
+	item.TotalOccurrences++
+	return item
+}
+
+// Title of the item for an occurrence: the first line of its title if it
+// has one, otherwise of the exception or the message
+func itemTitle(data *rollbar.OccurrenceData) string {
+	if data.Title != "" {
+		return strings.SplitN(data.Title, "\n", 2)[0]
+	}
+
+	body := &data.Body
+	var trace *rollbar.NotifierTrace
+	switch body.Kind() {
+	case rollbar.BODY_MESSAGE:
+		return strings.SplitN(body.Message.Body, "\n", 2)[0]
+	case rollbar.BODY_CRASH_REPORT:
+		return strings.SplitN(body.CrashReport.Raw, "\n", 2)[0]
+	case rollbar.BODY_TRACE:
+		trace = body.Trace
+	case rollbar.BODY_TRACE_CHAIN:
+		if len(body.TraceChain) > 0 {
+			trace = body.TraceChain[0]
+		}
+	}
+	if trace == nil || trace.Exception == nil {
+		return ""
+	}
+	if trace.Exception.Message != "" {
+		return trace.Exception.Class + ": " + trace.Exception.Message
+	}
+	return trace.Exception.Class
+}
+
+func (self *Server) itemByID(id uint64) *rollbar.Item {
+	for _, item := range self.items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+func (self *Server) itemByCounter(counter uint64) *rollbar.Item {
+	if counter == 0 || counter > uint64(len(self.items)) {
+		return nil
+	}
+	return self.items[counter-1]
+}
+
+func (self *Server) getItem(w http.ResponseWriter, item *rollbar.Item) {
+	if item == nil {
+		writeError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	writeResult(w, item)
+}
+
+func (self *Server) patchItem(w http.ResponseWriter, item *rollbar.Item, body []byte) {
+	if item == nil {
+		writeError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	var update struct {
+		Status *rollbar.ItemStatus        `json:"status"`
+		Level  *rollbar.NotificationLevel `json:"level"`
+		Title  *string                    `json:"title"`
+	}
+	if err := json.Unmarshal(body, &update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
+		return
+	}
+
+	if update.Status != nil && !update.Status.IsValid() {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Invalid status: %s", *update.Status))
+		return
+	}
+	if update.Level != nil && !update.Level.IsValid() {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Invalid level: %s", *update.Level))
+		return
+	}
+
+	now := rollbar.JSONTime{Time: time.Now()}
+	if update.Status != nil && *update.Status != item.Status {
+		item.Status = *update.Status
+		switch item.Status {
+		case rollbar.ITEM_RESOLVED:
+			item.LastResolvedTimestamp = now
+		case rollbar.ITEM_MUTED:
+			item.LastMutedTimestamp = now
+		case rollbar.ITEM_ACTIVE:
+			item.LastActivatedTimestamp = now
+		}
+	}
+	if update.Level != nil {
+		item.Level = string(*update.Level)
+	}
+	if update.Title != nil {
+		item.Title = *update.Title
+	}
+
+	writeResult(w, item)
+}
+
+// List occurrences newest first, either all of them or just an item's
+func (self *Server) getOccurrences(w http.ResponseWriter, req *http.Request, item_id uint64) {
+	if item_id != 0 && self.itemByID(item_id) == nil {
+		writeError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	page := uint64(1)
+	if s := req.URL.Query().Get("page"); s != "" {
+		var err error
+		if page, err = strconv.ParseUint(s, 10, 64); err != nil || page == 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid page: %s", s))
+			return
+		}
+	}
+
+	page_size := self.PageSize
+	if page_size <= 0 {
+		page_size = DEFAULT_PAGE_SIZE
+	}
+	skip := (page - 1) * uint64(page_size)
+
+	instances := []*rollbar.Occurrence{}
+	for i := len(self.occurrences) - 1; i >= 0 && len(instances) < page_size; i-- {
+		occur := self.occurrences[i]
+		if item_id != 0 && occur.itemID != item_id {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		instances = append(instances, occur.Occurrence)
+	}
+
+	writeResult(w, map[string]interface{}{
+		"instances": instances,
+		"page":      page,
+	})
+}
+
+func (self *Server) getOccurrence(w http.ResponseWriter, id uint64) {
+	for _, occur := range self.occurrences {
+		if occur.ID == id {
+			writeResult(w, occur.Occurrence)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Occurrence not found")
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"err":    0,
+		"result": result,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"err":     1,
+		"message": msg,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,331 @@
+package rollbartest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+func TestServerGroupsItems(t *testing.T) {
+	type post struct {
+		env         string
+		fingerprint string
+		title       string
+		message     string
+	}
+
+	tests := []struct {
+		name   string
+		posts  []post
+		titles []string
+		counts []uint64
+	}{
+		{
+			"same message",
+			[]post{{message: "oops"}, {message: "oops"}},
+			[]string{"oops"},
+			[]uint64{2},
+		},
+		{
+			"different messages",
+			[]post{{message: "oops"}, {message: "argh"}},
+			[]string{"oops", "argh"},
+			[]uint64{1, 1},
+		},
+		{
+			"first line of the message",
+			[]post{{message: "oops\nat 1"}, {message: "oops\nat 2"}},
+			[]string{"oops"},
+			[]uint64{2},
+		},
+		{
+			"title over message",
+			[]post{{title: "Save failed", message: "a"}, {title: "Save failed", message: "b"}},
+			[]string{"Save failed"},
+			[]uint64{2},
+		},
+		{
+			"fingerprint over title",
+			[]post{{fingerprint: "fp", title: "one"}, {fingerprint: "fp", title: "two"}, {title: "two"}},
+			[]string{"one", "two"},
+			[]uint64{2, 1},
+		},
+		{
+			"environment",
+			[]post{{env: "prod", message: "oops"}, {env: "staging", message: "oops"}},
+			[]string{"oops", "oops"},
+			[]uint64{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer()
+			defer server.Close()
+			client := server.NewClient()
+
+			for _, p := range tt.posts {
+				notif := client.NewMessageNotification(rollbar.LV_ERROR, p.message, nil)
+				notif.Environment = p.env
+				notif.Fingerprint = p.fingerprint
+				notif.Title = p.title
+				if _, err := client.SendNotification(notif); err != nil {
+					t.Fatalf("Error posting: %s", err)
+				}
+			}
+
+			items := server.Items()
+			if len(items) != len(tt.titles) {
+				t.Fatalf("Expected %d items, got %d", len(tt.titles), len(items))
+			}
+			for i, item := range items {
+				if item.Title != tt.titles[i] || item.TotalOccurrences != tt.counts[i] {
+					t.Errorf("Item %d: expected %q x%d, got %q x%d", i, tt.titles[i], tt.counts[i], item.Title, item.TotalOccurrences)
+				}
+				if item.Counter != uint64(i+1) {
+					t.Errorf("Item %d: expected counter %d, got %d", i, i+1, item.Counter)
+				}
+			}
+			if n := len(server.Occurrences()); n != len(tt.posts) {
+				t.Errorf("Expected %d occurrences, got %d", len(tt.posts), n)
+			}
+		})
+	}
+}
+
+func TestServerItemCounters(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	client.SendNotification(client.NewMessageNotification(rollbar.LV_ERROR, "oops", nil))
+	client.SendNotification(client.NewMessageNotification(rollbar.LV_ERROR, "oops", nil))
+	occurs := server.Occurrences()
+
+	item := server.ItemByCounter(1)
+	if item == nil {
+		t.Fatalf("Expected item 1")
+	}
+	if item.FirstOccurrenceId != occurs[0].ID || item.LastOccurrenceId != occurs[1].ID {
+		t.Errorf("Expected first/last occurrences %d/%d, got %d/%d", occurs[0].ID, occurs[1].ID, item.FirstOccurrenceId, item.LastOccurrenceId)
+	}
+	if server.ItemByCounter(2) != nil {
+		t.Errorf("Expected no item 2")
+	}
+
+	got, err := client.GetItemByCounter(1)
+	if err != nil || got.Item.ID != item.ID {
+		t.Fatalf("Expected item %d from the API, got %v (%v)", item.ID, got, err)
+	}
+
+	if err := client.SetItemStatus(item.ID, rollbar.ITEM_RESOLVED); err != nil {
+		t.Fatalf("Error resolving: %s", err)
+	}
+	client.SendNotification(client.NewMessageNotification(rollbar.LV_ERROR, "oops", nil))
+
+	item = server.ItemByCounter(1)
+	if item.Status != rollbar.ITEM_ACTIVE || item.TotalOccurrences != 3 || item.ActivatingOccurrenceId != server.Occurrences()[2].ID {
+		t.Errorf("Expected a new occurrence to reactivate the item, got %+v", item)
+	}
+}
+
+func TestServerPaging(t *testing.T) {
+	tests := []struct {
+		name     string
+		posted   int
+		pageSize int
+		page     uint64
+		want     int
+	}{
+		{"empty", 0, 0, 1, 0},
+		{"first page", 25, 0, 1, DEFAULT_PAGE_SIZE},
+		{"last page", 25, 0, 2, 5},
+		{"past the end", 25, 0, 3, 0},
+		{"page size", 7, 3, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer()
+			defer server.Close()
+			if tt.pageSize != 0 {
+				server.PageSize = tt.pageSize
+			}
+			client := server.NewClient()
+			for i := 0; i < tt.posted; i++ {
+				client.SendNotification(client.NewMessageNotification(rollbar.LV_ERROR, "oops", nil))
+			}
+
+			resp, err := client.GetOccurrencesWithPage(tt.page)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(resp.Occurrences) != tt.want {
+				t.Fatalf("Expected %d occurrences, got %d", tt.want, len(resp.Occurrences))
+			}
+
+			// Newest first
+			all := server.Occurrences()
+			page_size := server.PageSize
+			for i, occur := range resp.Occurrences {
+				want := all[len(all)-1-int(tt.page-1)*page_size-i]
+				if occur.ID != want.ID {
+					t.Fatalf("Occurrence %d: expected %d, got %d", i, want.ID, occur.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestServerFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    func(server *Server)
+		want    []int
+		message string
+	}{
+		{
+			"fail next",
+			func(server *Server) { server.FailNext(2, http.StatusServiceUnavailable) },
+			[]int{503, 503, 200},
+			"Service Unavailable",
+		},
+		{
+			"default status",
+			func(server *Server) { server.Fail(&Failure{Times: 1}) },
+			[]int{500, 200},
+			"Internal Server Error",
+		},
+		{
+			"matching path and message",
+			func(server *Server) {
+				server.Fail(&Failure{Path: "/item_by_counter/", StatusCode: 429, Message: "Slow down", Times: 1})
+			},
+			[]int{429, 200},
+			"Slow down",
+		},
+		{
+			"non-matching method",
+			func(server *Server) { server.Fail(&Failure{Method: "PATCH"}) },
+			[]int{200, 200},
+			"",
+		},
+		{
+			"until cleared",
+			func(server *Server) { server.Fail(&Failure{StatusCode: 502}) },
+			[]int{502, 502, 502},
+			"Bad Gateway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer()
+			defer server.Close()
+			client := server.NewClient()
+			client.SendNotification(client.NewMessageNotification(rollbar.LV_ERROR, "oops", nil))
+			tt.fail(server)
+
+			for i, want := range tt.want {
+				_, err := client.GetItemByCounter(1)
+				status := http.StatusOK
+				if err != nil {
+					api_err, ok := rollbar.AsAPIError(err)
+					if !ok {
+						t.Fatalf("Request %d: expected an *APIError, got %v", i, err)
+					}
+					status = api_err.StatusCode
+					if api_err.Message != tt.message {
+						t.Errorf("Request %d: expected message %q, got %q", i, tt.message, api_err.Message)
+					}
+				}
+				if status != want {
+					t.Errorf("Request %d: expected status %d, got %d", i, want, status)
+				}
+			}
+
+			server.ClearFailures()
+			if _, err := client.GetItemByCounter(1); err != nil {
+				t.Errorf("Unexpected error after ClearFailures(): %s", err)
+			}
+		})
+	}
+}
+
+func TestServerLatency(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	server.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	client.GetOccurrences()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected the response to take at least 50ms, took %s", elapsed)
+	}
+
+	// Latency gives up when the request does
+	server.SetLatency(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range client.WatchOccurrences(ctx, nil) {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Request wasn't abandoned when its context was done")
+	}
+
+	server.Reset()
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("Expected no requests after Reset(), got %d", n)
+	}
+}
+
+func TestServerAccessToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverTok  string
+		clientTok  string
+		wantStatus int
+	}{
+		{"any token", "", "whatever", http.StatusOK},
+		{"matching token", "secret", "secret", http.StatusOK},
+		{"wrong token", "secret", "other", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer()
+			defer server.Close()
+			server.AccessToken = tt.serverTok
+
+			client, _ := rollbar.NewClient(tt.clientTok)
+			client.SetAPIBaseURL(server.URL)
+
+			status := http.StatusOK
+			if _, err := client.GetOccurrences(); err != nil {
+				api_err, ok := rollbar.AsAPIError(err)
+				if !ok {
+					t.Fatalf("Expected an *APIError, got %v", err)
+				}
+				status = api_err.StatusCode
+			}
+			if status != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, status)
+			}
+
+			requests := server.Requests()
+			if len(requests) != 1 || requests[0].Token != tt.clientTok {
+				t.Errorf("Expected one request with token %q, got %+v", tt.clientTok, requests)
+			}
+		})
+	}
+}
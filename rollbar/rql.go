@@ -0,0 +1,297 @@
+package rollbar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_RQL_POLL_INTERVAL     = time.Second
+	DEFAULT_RQL_MAX_POLL_INTERVAL = 30 * time.Second
+)
+
+type RQLJobStatus string
+
+const (
+	RQL_NEW       RQLJobStatus = RQLJobStatus("new")
+	RQL_RUNNING   RQLJobStatus = RQLJobStatus("running")
+	RQL_SUCCESS   RQLJobStatus = RQLJobStatus("success")
+	RQL_FAILED    RQLJobStatus = RQLJobStatus("failed")
+	RQL_CANCELLED RQLJobStatus = RQLJobStatus("cancelled")
+	RQL_TIMED_OUT RQLJobStatus = RQLJobStatus("timed_out")
+)
+
+// Has the job stopped running?
+func (self RQLJobStatus) IsDone() bool {
+	switch self {
+	case RQL_NEW, RQL_RUNNING:
+		return false
+	}
+	return true
+}
+
+// RQL job object as returned from API
+type RQLJob struct {
+	ID           uint64       `json:"id"`
+	ProjectID    uint64       `json:"project_id"`
+	QueryString  string       `json:"query_string"`
+	Status       RQLJobStatus `json:"status"`
+	JobHash      string       `json:"job_hash"`
+	DateCreated  JSONTime     `json:"date_created"`
+	DateModified JSONTime     `json:"date_modified"`
+}
+
+// String representation in pretty JSON form
+func (self *RQLJob) String() string {
+	return self.AsPrettyJSON()
+}
+
+// RQL job as json string
+func (self *RQLJob) AsJSON() string {
+	return asJSON(self)
+}
+
+// RQL job as pretty json
+func (self *RQLJob) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Full API response for a single RQL job
+type RQLJobResponse struct {
+	BaseAPIResponse
+	*RQLJob `json:"result"`
+}
+
+// Columnar results of an RQL query. Each row holds one value per column,
+// in the same order as Columns.
+type RQLResult struct {
+	IsSimpleSelect     bool            `json:"isSimpleSelect"`
+	Errors             []string        `json:"errors"`
+	Warnings           []string        `json:"warnings"`
+	ExecutionTime      float64         `json:"executionTime"`
+	EffectiveTimestamp JSONTime        `json:"effectiveTimestamp"`
+	RowCount           uint64          `json:"rowcount"`
+	SelectionColumns   []string        `json:"selectionColumns"`
+	Columns            []string        `json:"columns"`
+	Rows               [][]interface{} `json:"rows"`
+}
+
+// Index of a column by name, or -1 if the result doesn't have it
+func (self *RQLResult) ColumnIndex(name string) int {
+	for i, column := range self.Columns {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// All values of a single column, or nil if the result doesn't have it
+func (self *RQLResult) Column(name string) []interface{} {
+	idx := self.ColumnIndex(name)
+	if idx < 0 {
+		return nil
+	}
+	values := make([]interface{}, len(self.Rows))
+	for i, row := range self.Rows {
+		if idx < len(row) {
+			values[i] = row[idx]
+		}
+	}
+	return values
+}
+
+// Rows as maps of column name to value
+func (self *RQLResult) RowMaps() []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(self.Rows))
+	for i, row := range self.Rows {
+		m := make(map[string]interface{}, len(self.Columns))
+		for j, column := range self.Columns {
+			if j < len(row) {
+				m[column] = row[j]
+			} else {
+				m[column] = nil
+			}
+		}
+		rows[i] = m
+	}
+	return rows
+}
+
+// RQL job along with its results
+type RQLJobResult struct {
+	RQLJob
+	Result *RQLResult `json:"result"`
+}
+
+// String representation in pretty JSON form
+func (self *RQLJobResult) String() string {
+	return asPrettyJSON(self)
+}
+
+// Full API response for an RQL job's results
+type RQLJobResultResponse struct {
+	BaseAPIResponse
+	*RQLJobResult `json:"result"`
+}
+
+// Options for RunRQLQuery()
+type RQLOptions struct {
+	// Run the query even if a cached result is available
+	ForceRefresh bool
+
+	// Time to wait before first checking the job. Doubles on every
+	// check up to MaxPollInterval. Defaults to DEFAULT_RQL_POLL_INTERVAL
+	PollInterval time.Duration
+
+	// Defaults to DEFAULT_RQL_MAX_POLL_INTERVAL
+	MaxPollInterval time.Duration
+}
+
+// Submit an RQL query as a job
+func (self *client) CreateRQLJob(ctx context.Context, query string, force_refresh bool) (*RQLJobResponse, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("Query is required")
+	}
+
+	job := map[string]interface{}{
+		"query_string":  query,
+		"force_refresh": force_refresh,
+	}
+
+	job_resp := &RQLJobResponse{}
+
+	err := self.httpCallWithContext(ctx, scopeRead, "POST", "/rql/jobs", nil, job, &job_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return job_resp, nil
+}
+
+// Get an RQL job by its id
+func (self *client) GetRQLJob(ctx context.Context, id uint64) (*RQLJobResponse, error) {
+	job_resp := &RQLJobResponse{}
+
+	err := self.httpCallWithContext(ctx, scopeRead, "GET", fmt.Sprintf("/rql/job/%d", id), nil, nil, &job_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return job_resp, nil
+}
+
+// Get an RQL job along with its results. Results are only present once
+// the job has succeeded.
+func (self *client) GetRQLJobResult(ctx context.Context, id uint64) (*RQLJobResultResponse, error) {
+	result_resp := &RQLJobResultResponse{}
+
+	err := self.httpCallWithContext(ctx, scopeRead, "GET", fmt.Sprintf("/rql/job/%d/result", id), nil, nil, &result_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return result_resp, nil
+}
+
+// Cancel a running RQL job
+func (self *client) CancelRQLJob(ctx context.Context, id uint64) (*RQLJobResponse, error) {
+	job_resp := &RQLJobResponse{}
+
+	err := self.httpCallWithContext(ctx, scopeRead, "POST", fmt.Sprintf("/rql/job/%d/cancel", id), nil, nil, &job_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return job_resp, nil
+}
+
+// Submit an RQL query and wait for its results. If ctx is done before the
+// job finishes, the job is cancelled and ctx's error is returned. If the
+// query itself reports errors, they're returned along with the results.
+func (self *client) RunRQLQuery(ctx context.Context, query string, options *RQLOptions) (*RQLJobResult, error) {
+	opts := RQLOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DEFAULT_RQL_POLL_INTERVAL
+	}
+	if opts.MaxPollInterval <= 0 {
+		opts.MaxPollInterval = DEFAULT_RQL_MAX_POLL_INTERVAL
+	}
+
+	job_resp, err := self.CreateRQLJob(ctx, query, opts.ForceRefresh)
+	if err != nil {
+		return nil, err
+	}
+	if job_resp.RQLJob == nil {
+		return nil, fmt.Errorf("Error creating RQL job: %s", job_resp.Message)
+	}
+
+	job := job_resp.RQLJob
+	interval := opts.PollInterval
+
+	for !job.Status.IsDone() {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			self.cancelRQLJob(job.ID)
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if interval *= 2; interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+
+		job_resp, err = self.GetRQLJob(ctx, job.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				self.cancelRQLJob(job.ID)
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		if job_resp.RQLJob == nil {
+			return nil, fmt.Errorf("Error getting RQL job %d: %s", job.ID, job_resp.Message)
+		}
+		job = job_resp.RQLJob
+	}
+
+	if job.Status != RQL_SUCCESS {
+		return nil, fmt.Errorf("RQL job %d %s", job.ID, job.Status)
+	}
+
+	result_resp, err := self.GetRQLJobResult(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	if result_resp.RQLJobResult == nil {
+		return nil, fmt.Errorf("Error getting RQL job %d result: %s", job.ID, result_resp.Message)
+	}
+
+	result := result_resp.RQLJobResult
+	if result.Result == nil {
+		return result, fmt.Errorf("RQL job %d returned no result", job.ID)
+	}
+	if len(result.Result.Errors) > 0 {
+		return result, fmt.Errorf("RQL query failed: %s", strings.Join(result.Result.Errors, "; "))
+	}
+
+	return result, nil
+}
+
+// Best effort cancel of a job whose caller has gone away
+func (self *client) cancelRQLJob(id uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := self.CancelRQLJob(ctx, id); err != nil && self.Logger != nil {
+		self.Logger.Printf("Error cancelling RQL job %d: %s", id, err)
+	}
+}
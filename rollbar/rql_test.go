@@ -0,0 +1,144 @@
+package rollbar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunRQLQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []RQLJobStatus
+		result   string
+		wantErr  string
+		wantRows string
+	}{
+		{
+			"immediate",
+			[]RQLJobStatus{RQL_SUCCESS},
+			`{"columns": ["level", "count"], "rows": [["error", 3]]}`,
+			"",
+			"[map[count:3 level:error]]",
+		},
+		{
+			"polled",
+			[]RQLJobStatus{RQL_NEW, RQL_RUNNING, RQL_RUNNING, RQL_SUCCESS},
+			`{"columns": ["level"], "rows": [["error"], ["warning"]]}`,
+			"",
+			"[map[level:error] map[level:warning]]",
+		},
+		{
+			"job failed",
+			[]RQLJobStatus{RQL_RUNNING, RQL_FAILED},
+			"",
+			"RQL job 7 failed",
+			"",
+		},
+		{
+			"query errors",
+			[]RQLJobStatus{RQL_SUCCESS},
+			`{"errors": ["Unknown column foo"], "columns": [], "rows": []}`,
+			"RQL query failed: Unknown column foo",
+			"[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				path := strings.TrimPrefix(req.URL.Path, "/api/1")
+				requests = append(requests, req.Method+" "+path)
+
+				status := tt.statuses[0]
+				if path == "/rql/job/7" {
+					if len(tt.statuses) > 1 {
+						tt.statuses = tt.statuses[1:]
+					}
+					status = tt.statuses[0]
+				}
+				if path == "/rql/job/7/result" {
+					fmt.Fprintf(w, `{"err": 0, "result": {"id": 7, "status": "success", "result": %s}}`, tt.result)
+					return
+				}
+				fmt.Fprintf(w, `{"err": 0, "result": {"id": 7, "status": %q}}`, status)
+			})
+
+			result, err := c.RunRQLQuery(context.Background(), "select level from item_occurrence", &RQLOptions{PollInterval: time.Millisecond})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if tt.wantRows == "" {
+				if result != nil {
+					t.Errorf("Expected no result, got %v", result)
+				}
+				return
+			}
+			if rows := fmt.Sprint(result.Result.RowMaps()); rows != tt.wantRows {
+				t.Errorf("Expected rows %s, got %s", tt.wantRows, rows)
+			}
+			if last := requests[len(requests)-1]; last != "GET /rql/job/7/result" {
+				t.Errorf("Expected the result to be fetched last, got %s", last)
+			}
+		})
+	}
+}
+
+func TestRunRQLQueryCancelled(t *testing.T) {
+	var lock sync.Mutex
+	cancelled := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if strings.HasSuffix(req.URL.Path, "/cancel") {
+			close(cancelled)
+			w.Write([]byte(`{"err": 0, "result": {"id": 7, "status": "cancelled"}}`))
+			return
+		}
+		w.Write([]byte(`{"err": 0, "result": {"id": 7, "status": "running"}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.RunRQLQuery(ctx, "select 1", &RQLOptions{PollInterval: 5 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the context's error, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the job to be cancelled")
+	}
+}
+
+func TestRQLResultColumns(t *testing.T) {
+	result := &RQLResult{
+		Columns: []string{"level", "count"},
+		Rows:    [][]interface{}{{"error", 3}, {"warning"}},
+	}
+
+	if idx := result.ColumnIndex("count"); idx != 1 {
+		t.Errorf("Expected count at index 1, got %d", idx)
+	}
+	if column := fmt.Sprint(result.Column("count")); column != "[3 <nil>]" {
+		t.Errorf("Expected a nil for the short row, got %s", column)
+	}
+	if column := result.Column("missing"); column != nil {
+		t.Errorf("Expected nil for a missing column, got %v", column)
+	}
+	if rows := fmt.Sprint(result.RowMaps()); rows != "[map[count:3 level:error] map[count:<nil> level:warning]]" {
+		t.Errorf("Unexpected rows %s", rows)
+	}
+}
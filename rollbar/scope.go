@@ -0,0 +1,251 @@
+package rollbar
+
+import (
+	"context"
+	"sync"
+)
+
+type scopeContextKey struct{}
+
+// Reporting data attached to a context.Context. Notifications created
+// with a context (e.g. Client.NewTraceNotificationWithContext()) have the
+// scope's data merged in. A child scope inherits everything from its
+// parent and may override any of it. Scopes are safe for concurrent use.
+type Scope struct {
+	parent *Scope
+
+	lock        sync.RWMutex
+	person      *NotifierPerson
+	request     *NotifierRequest
+	requestFunc func() *NotifierRequest
+	custom      CustomInfo
+	context     string
+	fingerprint string
+}
+
+// Create a new scope with no parent
+func NewScope() *Scope {
+	return &Scope{}
+}
+
+// Create a new scope inheriting from this one
+func (self *Scope) NewChild() *Scope {
+	return &Scope{parent: self}
+}
+
+// Get the parent scope. Returns nil if there is none.
+func (self *Scope) Parent() *Scope {
+	return self.parent
+}
+
+// Return a context holding 'scope'
+func ContextWithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, scope)
+}
+
+// Get the scope from a context. Returns nil if there is none.
+func ScopeFromContext(ctx context.Context) *Scope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(scopeContextKey{}).(*Scope)
+	return scope
+}
+
+// Return a context holding a new scope that inherits from the context's
+// current scope (if any), along with the new scope.
+func WithScope(ctx context.Context) (context.Context, *Scope) {
+	var scope *Scope
+	if parent := ScopeFromContext(ctx); parent != nil {
+		scope = parent.NewChild()
+	} else {
+		scope = NewScope()
+	}
+	return ContextWithScope(ctx, scope), scope
+}
+
+func (self *Scope) SetPerson(person *NotifierPerson) *Scope {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.person = person
+	return self
+}
+
+func (self *Scope) SetRequest(req *NotifierRequest) *Scope {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.request = req
+	self.requestFunc = nil
+	return self
+}
+
+// Set a function building the request when a notification needs it,
+// instead of building it up front
+func (self *Scope) SetRequestFunc(fn func() *NotifierRequest) *Scope {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.request = nil
+	self.requestFunc = fn
+	return self
+}
+
+func (self *Scope) SetContext(context string) *Scope {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.context = context
+	return self
+}
+
+func (self *Scope) SetFingerprint(fingerprint string) *Scope {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.fingerprint = fingerprint
+	return self
+}
+
+// Set a single custom key
+func (self *Scope) SetCustom(key string, value interface{}) *Scope {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.custom == nil {
+		self.custom = CustomInfo{}
+	}
+	self.custom[key] = value
+	return self
+}
+
+// Get the person, looking at parents if not set on this scope
+func (self *Scope) GetPerson() *NotifierPerson {
+	for scope := self; scope != nil; scope = scope.parent {
+		scope.lock.RLock()
+		person := scope.person
+		scope.lock.RUnlock()
+		if person != nil {
+			return person
+		}
+	}
+	return nil
+}
+
+// Get the request, looking at parents if not set on this scope
+func (self *Scope) GetRequest() *NotifierRequest {
+	for scope := self; scope != nil; scope = scope.parent {
+		scope.lock.RLock()
+		req, fn := scope.request, scope.requestFunc
+		scope.lock.RUnlock()
+		if req == nil && fn != nil {
+			req = fn()
+		}
+		if req != nil {
+			return req
+		}
+	}
+	return nil
+}
+
+// Get the context string, looking at parents if not set on this scope
+func (self *Scope) GetContext() string {
+	for scope := self; scope != nil; scope = scope.parent {
+		scope.lock.RLock()
+		context := scope.context
+		scope.lock.RUnlock()
+		if context != "" {
+			return context
+		}
+	}
+	return ""
+}
+
+// Get the fingerprint, looking at parents if not set on this scope
+func (self *Scope) GetFingerprint() string {
+	for scope := self; scope != nil; scope = scope.parent {
+		scope.lock.RLock()
+		fingerprint := scope.fingerprint
+		scope.lock.RUnlock()
+		if fingerprint != "" {
+			return fingerprint
+		}
+	}
+	return ""
+}
+
+// Get custom data merged from this scope and all parents. Keys in child
+// scopes override their parents'.
+func (self *Scope) GetCustom() CustomInfo {
+	var custom CustomInfo
+	if self.parent != nil {
+		custom = self.parent.GetCustom()
+	}
+
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if len(self.custom) == 0 {
+		return custom
+	}
+	if custom == nil {
+		custom = make(CustomInfo, len(self.custom))
+	}
+	for k, v := range self.custom {
+		custom[k] = v
+	}
+	return custom
+}
+
+// Merge the scope's data into a notification. Anything already set on
+// the notification takes precedence.
+func (self *Scope) Apply(notif Notification) Notification {
+	if self == nil {
+		return notif
+	}
+
+	if notif.GetPerson() == nil {
+		if person := self.GetPerson(); person != nil {
+			notif.SetPerson(person)
+		}
+	}
+	if notif.GetRequest() == nil {
+		if req := self.GetRequest(); req != nil {
+			notif.SetRequest(req)
+		}
+	}
+	if notif.GetContext() == "" {
+		notif.SetContext(self.GetContext())
+	}
+	if notif.GetFingerprint() == "" {
+		notif.SetFingerprint(self.GetFingerprint())
+	}
+
+	if custom := self.GetCustom(); custom != nil {
+		for k, v := range notif.GetCustom() {
+			custom[k] = v
+		}
+		notif.SetCustom(custom)
+	}
+
+	return notif
+}
+
+func (self *client) NewMessageNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *MessageNotification {
+	notif := self.NewMessageNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
+func (self *client) NewTraceNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *TraceNotification {
+	notif := self.NewTraceNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
+func (self *client) NewTraceChainNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *TraceChainNotification {
+	notif := self.NewTraceChainNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
+
+func (self *client) NewCrashReportNotificationWithContext(ctx context.Context, level NotificationLevel, message string, custom CustomInfo) *CrashReportNotification {
+	notif := self.NewCrashReportNotification(level, message, custom)
+	ScopeFromContext(ctx).Apply(notif)
+	return notif
+}
@@ -0,0 +1,133 @@
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestScopeInheritance(t *testing.T) {
+	parent := NewScope().
+		SetPerson(&NotifierPerson{ID: "1"}).
+		SetContext("parent").
+		SetFingerprint("parent-fp").
+		SetCustom("app", "api").
+		SetCustom("region", "us")
+	child := parent.NewChild().
+		SetContext("child").
+		SetCustom("region", "eu").
+		SetCustom("job", 7)
+
+	if child.Parent() != parent || parent.Parent() != nil {
+		t.Errorf("Expected the child's parent to be the parent scope")
+	}
+	if person := child.GetPerson(); person == nil || person.ID != "1" {
+		t.Errorf("Expected the person to be inherited, got %+v", person)
+	}
+	if child.GetContext() != "child" || parent.GetContext() != "parent" {
+		t.Errorf("Expected the child to override the context, got %q and %q", child.GetContext(), parent.GetContext())
+	}
+	if child.GetFingerprint() != "parent-fp" {
+		t.Errorf("Expected the fingerprint to be inherited, got %q", child.GetFingerprint())
+	}
+	if custom := fmt.Sprint(child.GetCustom()); custom != "map[app:api job:7 region:eu]" {
+		t.Errorf("Expected merged custom data, got %s", custom)
+	}
+	if custom := fmt.Sprint(parent.GetCustom()); custom != "map[app:api region:us]" {
+		t.Errorf("Expected the parent's custom data to be untouched, got %s", custom)
+	}
+}
+
+func TestWithScope(t *testing.T) {
+	if ScopeFromContext(context.Background()) != nil {
+		t.Errorf("Expected no scope in an empty context")
+	}
+
+	ctx, outer := WithScope(context.Background())
+	if outer.Parent() != nil || ScopeFromContext(ctx) != outer {
+		t.Errorf("Expected a new root scope in the context")
+	}
+
+	inner_ctx, inner := WithScope(ctx)
+	if inner.Parent() != outer || ScopeFromContext(inner_ctx) != inner {
+		t.Errorf("Expected a child of the context's scope")
+	}
+	if ScopeFromContext(ctx) != outer {
+		t.Errorf("Expected the outer context to keep its scope")
+	}
+}
+
+func TestNewNotificationWithContext(t *testing.T) {
+	c, _ := NewClient("test-token")
+
+	tests := []struct {
+		name            string
+		scope           *Scope
+		custom          CustomInfo
+		setup           func(notif Notification)
+		wantPerson      string
+		wantContext     string
+		wantFingerprint string
+		wantCustom      string
+	}{
+		{
+			"no scope",
+			nil,
+			CustomInfo{"a": 1},
+			nil,
+			"", "", "", "map[a:1]",
+		},
+		{
+			"scope applied",
+			NewScope().SetPerson(&NotifierPerson{ID: "1"}).SetContext("save").SetFingerprint("fp").SetCustom("app", "api"),
+			nil,
+			nil,
+			"1", "save", "fp", "map[app:api]",
+		},
+		{
+			"notification wins",
+			NewScope().SetContext("save").SetFingerprint("fp").SetCustom("app", "api").SetCustom("a", 0),
+			CustomInfo{"a": 1},
+			func(notif Notification) {
+				notif.SetContext("load")
+				notif.SetFingerprint("other")
+			},
+			"", "load", "other", "map[a:1 app:api]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.scope != nil {
+				ctx = ContextWithScope(ctx, tt.scope)
+			}
+
+			var notif Notification
+			if tt.setup == nil {
+				notif = c.NewMessageNotificationWithContext(ctx, LV_ERROR, "failed", tt.custom)
+			} else {
+				// Set what the notification should keep before the
+				// scope is applied
+				notif = c.NewMessageNotification(LV_ERROR, "failed", tt.custom)
+				tt.setup(notif)
+				ScopeFromContext(ctx).Apply(notif)
+			}
+
+			var person string
+			if p := notif.GetPerson(); p != nil {
+				person = p.ID
+			}
+			if person != tt.wantPerson {
+				t.Errorf("Expected person %q, got %q", tt.wantPerson, person)
+			}
+			if notif.GetContext() != tt.wantContext || notif.GetFingerprint() != tt.wantFingerprint {
+				t.Errorf("Expected context %q and fingerprint %q, got %q and %q",
+					tt.wantContext, tt.wantFingerprint, notif.GetContext(), notif.GetFingerprint())
+			}
+			if custom := fmt.Sprint(notif.GetCustom()); custom != tt.wantCustom {
+				t.Errorf("Expected custom %s, got %s", tt.wantCustom, custom)
+			}
+		})
+	}
+}
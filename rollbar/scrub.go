@@ -0,0 +1,149 @@
+package rollbar
+
+import (
+	net_url "net/url"
+	"strings"
+)
+
+// Value that replaces anything scrubbed
+const SCRUBBED_VALUE = "********"
+
+// Default field names (headers, params) whose values are scrubbed before
+// being sent to rollbar. Matching is case insensitive.
+var DefaultScrubFields = []string{
+	"passwd",
+	"password",
+	"secret",
+	"confirm_password",
+	"password_confirmation",
+	"auth_token",
+	"authenticity_token",
+	"access_token",
+	"api_key",
+	"apikey",
+	"token",
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+	"x-rollbar-access-token",
+}
+
+func shouldScrub(fields []string, name string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func scrubStringMap(fields []string, m map[string]string) {
+	for k := range m {
+		if shouldScrub(fields, k) {
+			m[k] = SCRUBBED_VALUE
+		}
+	}
+}
+
+// Scrub a decoded JSON object in place, including any nested objects
+func scrubObject(fields []string, obj map[string]interface{}) {
+	for k, v := range obj {
+		if shouldScrub(fields, k) {
+			obj[k] = SCRUBBED_VALUE
+			continue
+		}
+		scrubNested(fields, v)
+	}
+}
+
+func scrubNested(fields []string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		scrubObject(fields, v)
+	case []interface{}:
+		for _, elem := range v {
+			scrubNested(fields, elem)
+		}
+	}
+}
+
+func scrubQuery(fields []string, query net_url.Values) bool {
+	scrubbed := false
+	for k, v := range query {
+		if shouldScrub(fields, k) {
+			for i := range v {
+				v[i] = SCRUBBED_VALUE
+			}
+			scrubbed = true
+		}
+	}
+	return scrubbed
+}
+
+// Scrub a raw query string, returning the new query string. If nothing
+// needed scrubbing (or it can't be parsed), the original is returned.
+func scrubQueryString(fields []string, query_str string) string {
+	query, err := net_url.ParseQuery(query_str)
+	if err != nil || !scrubQuery(fields, query) {
+		return query_str
+	}
+	return query.Encode()
+}
+
+// Scrub a URL's password and any query params that match 'fields'. If
+// 'fields' is nil, DefaultScrubFields is used.
+func ScrubURL(raw_url string, fields []string) string {
+	if fields == nil {
+		fields = DefaultScrubFields
+	}
+
+	u, err := net_url.Parse(raw_url)
+	if err != nil {
+		return raw_url
+	}
+
+	changed := false
+	if u.User != nil {
+		if _, has_pw := u.User.Password(); has_pw {
+			u.User = net_url.UserPassword(u.User.Username(), SCRUBBED_VALUE)
+			changed = true
+		}
+	}
+
+	if u.RawQuery != "" {
+		query_str := scrubQueryString(fields, u.RawQuery)
+		if query_str != u.RawQuery {
+			u.RawQuery = query_str
+			changed = true
+		}
+	}
+
+	if !changed {
+		return raw_url
+	}
+	return u.String()
+}
+
+// Scrub sensitive values from headers, params, query string and URL in
+// place. If 'fields' is nil, DefaultScrubFields is used.
+func (self *NotifierRequest) Scrub(fields []string) *NotifierRequest {
+	if fields == nil {
+		fields = DefaultScrubFields
+	}
+
+	if len(self.URL) != 0 {
+		self.URL = ScrubURL(self.URL, fields)
+	}
+	if len(self.QueryString) != 0 {
+		self.QueryString = scrubQueryString(fields, self.QueryString)
+	}
+
+	scrubStringMap(fields, self.Headers)
+	scrubStringMap(fields, self.Params)
+	scrubStringMap(fields, self.GETParams)
+
+	scrubObject(fields, self.POSTParams)
+
+	return self
+}
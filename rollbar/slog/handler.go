@@ -0,0 +1,229 @@
+// Package rollbarslog provides a log/slog Handler that reports to rollbar.
+package rollbarslog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+)
+
+// Options for the handler
+type HandlerOptions struct {
+	// Records at or above this level are sent as notifications. Defaults
+	// to slog.LevelError
+	Level slog.Leveler
+
+	// Records below Level but at or above this are added as telemetry.
+	// Defaults to slog.LevelInfo
+	TelemetryLevel slog.Leveler
+}
+
+// slog.Handler that passes records to another handler and also reports
+// them to rollbar
+type Handler struct {
+	client  rollbar.Client
+	next    slog.Handler
+	options HandlerOptions
+
+	// WithGroup() and WithAttrs() calls, in order
+	goas []groupOrAttrs
+}
+
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// Create a new handler wrapping 'next'. 'options' may be nil to use the
+// defaults.
+func NewHandler(client rollbar.Client, next slog.Handler, options *HandlerOptions) *Handler {
+	self := &Handler{
+		client: client,
+		next:   next,
+	}
+	if options != nil {
+		self.options = *options
+	}
+	if self.options.Level == nil {
+		self.options.Level = slog.LevelError
+	}
+	if self.options.TelemetryLevel == nil {
+		self.options.TelemetryLevel = slog.LevelInfo
+	}
+	return self
+}
+
+// Map a slog level to a rollbar level
+func LevelFromSlog(level slog.Level) rollbar.NotificationLevel {
+	switch {
+	case level >= slog.LevelError+4:
+		return rollbar.LV_CRITICAL
+	case level >= slog.LevelError:
+		return rollbar.LV_ERROR
+	case level >= slog.LevelWarn:
+		return rollbar.LV_WARNING
+	case level >= slog.LevelInfo:
+		return rollbar.LV_INFO
+	default:
+		return rollbar.LV_DEBUG
+	}
+}
+
+func (self *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return self.next.Enabled(ctx, level) ||
+		level >= min(self.options.Level.Level(), self.options.TelemetryLevel.Level())
+}
+
+func (self *Handler) Handle(ctx context.Context, rec slog.Record) error {
+	var err error
+	if self.next.Enabled(ctx, rec.Level) {
+		err = self.next.Handle(ctx, rec)
+	}
+
+	if rec.Level >= self.options.Level.Level() {
+		self.notify(ctx, rec)
+	} else if rec.Level >= self.options.TelemetryLevel.Level() {
+		custom, _ := self.custom(rec)
+		entry := rollbar.NewLogTelemetry(LevelFromSlog(rec.Level), rec.Message, custom)
+		if !rec.Time.IsZero() {
+			entry.SetTimestamp(rec.Time)
+		}
+		self.client.AddTelemetry(entry)
+	}
+
+	return err
+}
+
+func (self *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	return self.with(groupOrAttrs{attrs: attrs}, self.next.WithAttrs(attrs))
+}
+
+func (self *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return self
+	}
+	return self.with(groupOrAttrs{group: name}, self.next.WithGroup(name))
+}
+
+func (self *Handler) with(goa groupOrAttrs, next slog.Handler) *Handler {
+	h := *self
+	h.next = next
+	h.goas = append(self.goas[:len(self.goas):len(self.goas)], goa)
+	return &h
+}
+
+func (self *Handler) notify(ctx context.Context, rec slog.Record) {
+	level := LevelFromSlog(rec.Level)
+	custom, err := self.custom(rec)
+
+	var notif rollbar.Notification
+	if err != nil {
+		trace_notif := self.client.NewTraceNotificationWithContext(ctx, level, rec.Message, custom)
+		trace_notif.Trace.AddExceptionFromError(err)
+		trace_notif.Trace.AddRuntimeFrames(callerFrames(rec.PC))
+		notif = trace_notif
+	} else {
+		notif = self.client.NewMessageNotificationWithContext(ctx, level, rec.Message, custom)
+	}
+
+	if !rec.Time.IsZero() {
+		notif.SetTimestamp(rec.Time)
+	}
+
+	rollbar.SendNotificationAsync(self.client, notif)
+}
+
+// Build CustomInfo from the handler's and record's attrs, also returning
+// the first error value found.
+func (self *Handler) custom(rec slog.Record) (rollbar.CustomInfo, error) {
+	var first_err error
+
+	custom := rollbar.CustomInfo{}
+	cur := map[string]interface{}(custom)
+
+	for _, goa := range self.goas {
+		if goa.group != "" {
+			group := map[string]interface{}{}
+			cur[goa.group] = group
+			cur = group
+			continue
+		}
+		for _, attr := range goa.attrs {
+			addAttr(cur, attr, &first_err)
+		}
+	}
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		addAttr(cur, attr, &first_err)
+		return true
+	})
+
+	return custom, first_err
+}
+
+func addAttr(m map[string]interface{}, attr slog.Attr, first_err *error) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		attrs := attr.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		group := m
+		// Inline groups with empty keys
+		if attr.Key != "" {
+			group = map[string]interface{}{}
+			m[attr.Key] = group
+		}
+		for _, ga := range attrs {
+			addAttr(group, ga, first_err)
+		}
+	case slog.KindTime:
+		m[attr.Key] = attr.Value.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		m[attr.Key] = attr.Value.Duration().String()
+	case slog.KindAny:
+		v := attr.Value.Any()
+		if err, ok := v.(error); ok {
+			if *first_err == nil {
+				*first_err = err
+			}
+			m[attr.Key] = err.Error()
+		} else {
+			m[attr.Key] = v
+		}
+	default:
+		m[attr.Key] = attr.Value.Any()
+	}
+}
+
+// Frames starting at the logging call site, if it can be found on the
+// current stack
+func callerFrames(pc uintptr) *runtime.Frames {
+	pcs := make([]uintptr, 100)
+	num := runtime.Callers(1, pcs)
+	pcs = pcs[:num]
+
+	if pc != 0 {
+		for i, p := range pcs {
+			if p == pc {
+				return runtime.CallersFrames(pcs[i:])
+			}
+		}
+		return runtime.CallersFrames([]uintptr{pc})
+	}
+
+	return runtime.CallersFrames(pcs)
+}
+
+var _ slog.Handler = (*Handler)(nil)
@@ -0,0 +1,135 @@
+package rollbarslog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	"github.com/comstud/go-rollbar/rollbar/rollbartest"
+	rollbarslog "github.com/comstud/go-rollbar/rollbar/slog"
+)
+
+func TestHandler(t *testing.T) {
+	save_err := errors.New("disk full")
+
+	tests := []struct {
+		name       string
+		log        func(logger *slog.Logger)
+		wantLevel  rollbar.NotificationLevel
+		wantTitle  string
+		wantTrace  bool
+		wantCustom string
+	}{
+		{
+			"below level",
+			func(logger *slog.Logger) { logger.Warn("slow", "ms", 500) },
+			"", "", false, "",
+		},
+		{
+			"error message",
+			func(logger *slog.Logger) { logger.Error("failed", "user", "bob") },
+			rollbar.LV_ERROR, "failed", false, "map[user:bob]",
+		},
+		{
+			"error value",
+			func(logger *slog.Logger) { logger.Error("save failed", "err", save_err) },
+			rollbar.LV_ERROR, "save failed", true, "map[err:disk full]",
+		},
+		{
+			"critical",
+			func(logger *slog.Logger) { logger.Log(context.Background(), slog.LevelError+4, "down") },
+			rollbar.LV_CRITICAL, "down", false, "map[]",
+		},
+		{
+			"groups and attrs",
+			func(logger *slog.Logger) {
+				logger.With("app", "api").WithGroup("req").With("id", 7).Error("failed", slog.Group("user", "name", "bob"))
+			},
+			rollbar.LV_ERROR, "failed", false, "map[app:api req:map[id:7 user:map[name:bob]]]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := rollbartest.NewRecorder()
+			var buf bytes.Buffer
+			next := slog.NewTextHandler(&buf, nil)
+			tt.log(slog.New(rollbarslog.NewHandler(recorder, next, nil)))
+
+			if buf.Len() == 0 {
+				t.Errorf("Expected the record to be passed on")
+			}
+
+			notifs := recorder.Notifications()
+			if tt.wantLevel == "" {
+				if len(notifs) != 0 {
+					t.Errorf("Expected nothing reported, got %d notifications", len(notifs))
+				}
+				return
+			}
+			if len(notifs) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(notifs))
+			}
+
+			notif := notifs[0]
+			if notif.GetLevel() != tt.wantLevel || notif.GetTitle() != tt.wantTitle {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantLevel, tt.wantTitle, notif.GetLevel(), notif.GetTitle())
+			}
+			if custom := fmt.Sprint(notif.GetCustom()); custom != tt.wantCustom {
+				t.Errorf("Expected custom %s, got %s", tt.wantCustom, custom)
+			}
+
+			trace, is_trace := notif.(*rollbar.TraceNotification)
+			if is_trace != tt.wantTrace {
+				t.Fatalf("Expected trace=%v, got %T", tt.wantTrace, notif)
+			}
+			if is_trace {
+				if trace.Trace.Exception == nil || trace.Trace.Exception.Message != "disk full" {
+					t.Errorf("Expected the error as the exception, got %+v", trace.Trace.Exception)
+				}
+				frames := trace.Trace.Frames
+				if len(frames) == 0 || !strings.Contains(frames[0].Method, "TestHandler") {
+					t.Errorf("Expected the logging call as the first frame")
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerReportsBelowTelemetryLevel(t *testing.T) {
+	recorder := rollbartest.NewRecorder()
+	next := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError})
+	handler := rollbarslog.NewHandler(recorder, next, &rollbarslog.HandlerOptions{
+		Level:          slog.LevelDebug,
+		TelemetryLevel: slog.LevelWarn,
+	})
+
+	slog.New(handler).Debug("cache miss")
+	if notifs := recorder.Notifications(); len(notifs) != 1 || notifs[0].GetTitle() != "cache miss" {
+		t.Errorf("Expected the debug record to be reported, got %d notifications", len(notifs))
+	}
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  rollbar.NotificationLevel
+	}{
+		{slog.LevelDebug, rollbar.LV_DEBUG},
+		{slog.LevelInfo, rollbar.LV_INFO},
+		{slog.LevelWarn, rollbar.LV_WARNING},
+		{slog.LevelError, rollbar.LV_ERROR},
+		{slog.LevelError + 4, rollbar.LV_CRITICAL},
+	}
+	for _, tt := range tests {
+		if got := rollbarslog.LevelFromSlog(tt.level); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.level, tt.want, got)
+		}
+	}
+}
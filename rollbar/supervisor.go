@@ -0,0 +1,200 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Environment variable marking a process as the crash watcher. Holds the
+// pid of the process being watched.
+const CRASH_SUPERVISOR_ENV = "ROLLBAR_CRASH_SUPERVISOR"
+
+// Bytes of crash output kept in custom data when it can't be parsed
+const maxCrashOutput = 64 * 1024
+
+// Options for StartCrashSupervisor()
+type SupervisorOptions struct {
+	// Level for crash notifications. Defaults to LV_CRITICAL
+	Level NotificationLevel
+
+	// Directory where crash notifications are written if they can't be
+	// delivered. They're sent the next time a watcher starts. Empty
+	// disables spooling.
+	SpoolDir string
+}
+
+// Start a crash supervisor so that fatal errors that can't be recovered
+// (concurrent map writes, out of memory, stack overflow, unrecovered
+// panics) are reported.
+//
+// This re-executes the current program as a watcher process and routes
+// the runtime's crash output to it with debug.SetCrashOutput(). Call it
+// early in main(), after creating the client: in the watcher process this
+// function does not return. Anything before the call runs in both
+// processes. The watcher sends the crash once the program exits.
+func StartCrashSupervisor(client Client, options *SupervisorOptions) error {
+	opts := SupervisorOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Level == "" {
+		opts.Level = LV_CRITICAL
+	}
+
+	if pid := os.Getenv(CRASH_SUPERVISOR_ENV); pid != "" {
+		runCrashWatcher(client, &opts, pid)
+		os.Exit(0)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer pw.Close()
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", CRASH_SUPERVISOR_ENV, os.Getpid()))
+	cmd.Stdin = pr
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Start()
+	pr.Close()
+	if err != nil {
+		return fmt.Errorf("Error starting crash watcher: %s", err)
+	}
+
+	// Reap the watcher if it exits first
+	go cmd.Wait()
+
+	return debug.SetCrashOutput(pw, debug.CrashOptions{})
+}
+
+func runCrashWatcher(client Client, options *SupervisorOptions, pid string) {
+	// Terminal signals go to the whole process group, and SIGTERM is often
+	// sent to it too (e.g. by container runtimes). Stay alive to see
+	// whether the program crashes as a result; the watcher exits once the
+	// program has, as its crash output pipe then closes.
+	signal.Ignore(syscall.SIGINT, syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGTERM)
+
+	logger := client.Options().Logger
+
+	if options.SpoolDir != "" {
+		sendSpooledCrashes(client, options.SpoolDir)
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil && logger != nil {
+		logger.Printf("Error reading crash output: %s", err)
+	}
+
+	output := string(data)
+	if strings.TrimSpace(output) == "" {
+		return
+	}
+
+	custom := CustomInfo{}
+	if pid_num, err := strconv.Atoi(pid); err == nil {
+		custom["pid"] = pid_num
+	}
+	if len(output) > maxCrashOutput {
+		custom["crash_output"] = output[len(output)-maxCrashOutput:]
+	} else {
+		custom["crash_output"] = output
+	}
+
+	var notif Notification
+	if trace := ParseGoTraceback(output); trace != nil {
+		message := trace.Exception.Class
+		if trace.Exception.Message != "" {
+			message += ": " + trace.Exception.Message
+		}
+		trace_notif := client.NewTraceNotification(options.Level, message, custom)
+		trace_notif.Trace = *trace
+		notif = trace_notif
+	} else {
+		first_line := strings.SplitN(strings.TrimSpace(output), "\n", 2)[0]
+		notif = client.NewMessageNotification(options.Level, first_line, custom)
+	}
+	notif.SetUUID(NewUUID())
+
+	if _, err = client.SendNotification(notif); err == nil {
+		return
+	}
+
+	if logger != nil {
+		logger.Printf("Error sending crash to rollbar: %s", err)
+	}
+	if options.SpoolDir != "" {
+		if err := spoolCrash(options.SpoolDir, notif); err != nil && logger != nil {
+			logger.Printf("Error spooling crash: %s", err)
+		}
+	}
+}
+
+func spoolCrash(dir string, notif Notification) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(notif)
+	if err != nil {
+		return err
+	}
+
+	// Write then rename, so a partial file is never picked up
+	path := filepath.Join(dir, notif.GetUUID()+".json")
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Send crashes spooled by previous watchers, removing them once delivered
+func sendSpooledCrashes(client Client, dir string) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var kind struct {
+			Body map[string]json.RawMessage `json:"body"`
+		}
+		if err := json.Unmarshal(data, &kind); err != nil {
+			os.Remove(path)
+			continue
+		}
+
+		var notif Notification
+		if _, ok := kind.Body["trace"]; ok {
+			notif = NewTraceNotification("", "", nil)
+		} else {
+			notif = NewMessageNotification("", "", nil)
+		}
+		if err := json.Unmarshal(data, notif); err != nil {
+			os.Remove(path)
+			continue
+		}
+
+		if _, err := client.SendNotification(notif); err != nil {
+			return
+		}
+		os.Remove(path)
+	}
+}
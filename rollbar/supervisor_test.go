@@ -0,0 +1,65 @@
+package rollbar
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestSpooledCrashes(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spool")
+
+	trace := NewTraceNotification(LV_CRITICAL, "fatal error: concurrent map writes", nil)
+	trace.SetUUID("trace")
+	trace.Trace.Exception = &NotifierException{Class: "fatal error", Message: "concurrent map writes"}
+	message := NewMessageNotification(LV_CRITICAL, "out of memory", nil)
+	message.SetUUID("message")
+
+	for _, notif := range []Notification{trace, message} {
+		if err := spoolCrash(dir, notif); err != nil {
+			t.Fatalf("Error spooling: %s", err)
+		}
+	}
+	// Left over from a watcher that died while spooling
+	os.WriteFile(filepath.Join(dir, "partial.json.tmp"), []byte("{"), 0600)
+
+	fail := true
+	var sent []string
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload struct {
+			Data struct {
+				UUID string                     `json:"uuid"`
+				Body map[string]json.RawMessage `json:"body"`
+			} `json:"data"`
+		}
+		json.NewDecoder(req.Body).Decode(&payload)
+		kind := "message"
+		if _, ok := payload.Data.Body["trace"]; ok {
+			kind = "trace"
+		}
+		sent = append(sent, payload.Data.UUID+":"+kind)
+		w.Write([]byte(`{"err": 0}`))
+	})
+
+	sendSpooledCrashes(c, dir)
+	if paths, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(paths) != 2 {
+		t.Fatalf("Expected crashes to stay spooled while sending fails, got %v", paths)
+	}
+
+	fail = false
+	sendSpooledCrashes(c, dir)
+	sort.Strings(sent)
+	if len(sent) != 2 || sent[0] != "message:message" || sent[1] != "trace:trace" {
+		t.Errorf("Expected both crashes sent as their own kind, got %v", sent)
+	}
+	if paths, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(paths) != 0 {
+		t.Errorf("Expected sent crashes to be removed, got %v", paths)
+	}
+}
@@ -0,0 +1,319 @@
+package rollbar
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type TeamAccessLevel string
+
+const (
+	TEAM_STANDARD TeamAccessLevel = TeamAccessLevel("standard")
+	TEAM_LIGHT    TeamAccessLevel = TeamAccessLevel("light")
+	TEAM_VIEW     TeamAccessLevel = TeamAccessLevel("view")
+	TEAM_OWNER    TeamAccessLevel = TeamAccessLevel("owner")
+)
+
+// Team object as returned from API
+type Team struct {
+	ID          uint64          `json:"id"`
+	AccountID   uint64          `json:"account_id"`
+	Name        string          `json:"name"`
+	AccessLevel TeamAccessLevel `json:"access_level"`
+}
+
+// String representation in pretty JSON form
+func (self *Team) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Team as json string
+func (self *Team) AsJSON() string {
+	return asJSON(self)
+}
+
+// Team as pretty json
+func (self *Team) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Full API response for a single team
+type TeamResponse struct {
+	BaseAPIResponse
+	*Team `json:"result"`
+}
+
+// Full API response for multiple teams
+type TeamsResponse struct {
+	BaseAPIResponse
+	Teams []*Team `json:"result"`
+}
+
+// Teams response as a pretty json string
+func (self *TeamsResponse) String() string {
+	return asPrettyJSON(self.Teams)
+}
+
+// Membership of a user in a team
+type TeamUser struct {
+	TeamID uint64 `json:"team_id"`
+	UserID uint64 `json:"user_id"`
+}
+
+// Full API response for a page of team users
+type TeamUsersResponse struct {
+	pageSize int
+	BaseAPIResponse
+	Page      uint64
+	TeamUsers []*TeamUser `json:"result"`
+}
+
+// Does a team users response have more pages? A page smaller than a full
+// one is the last.
+func (self *TeamUsersResponse) HasMorePages() bool {
+	if !self.IsSuccess() {
+		return false
+	}
+	page_size := self.pageSize
+	return pageHasMore(len(self.TeamUsers), &page_size)
+}
+
+// Team users response as a pretty json string
+func (self *TeamUsersResponse) String() string {
+	return asPrettyJSON(self.TeamUsers)
+}
+
+// Project a team has access to
+type TeamProject struct {
+	TeamID    uint64 `json:"team_id"`
+	ProjectID uint64 `json:"project_id"`
+}
+
+// Full API response for a team's projects
+type TeamProjectsResponse struct {
+	BaseAPIResponse
+	TeamProjects []*TeamProject `json:"result"`
+}
+
+// Team projects response as a pretty json string
+func (self *TeamProjectsResponse) String() string {
+	return asPrettyJSON(self.TeamProjects)
+}
+
+// Invite object as returned from API
+type Invite struct {
+	ID           uint64   `json:"id"`
+	FromUserID   uint64   `json:"from_user_id"`
+	TeamID       uint64   `json:"team_id"`
+	ToEmail      string   `json:"to_email"`
+	Status       string   `json:"status"`
+	DateCreated  JSONTime `json:"date_created"`
+	DateRedeemed JSONTime `json:"date_redeemed"`
+}
+
+// String representation in pretty JSON form
+func (self *Invite) String() string {
+	return self.AsPrettyJSON()
+}
+
+// Invite as json string
+func (self *Invite) AsJSON() string {
+	return asJSON(self)
+}
+
+// Invite as pretty json
+func (self *Invite) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Full API response for a single invite
+type InviteResponse struct {
+	BaseAPIResponse
+	*Invite `json:"result"`
+}
+
+// Full API response for a page of invites
+type InvitesResponse struct {
+	pageSize int
+	BaseAPIResponse
+	Page    uint64
+	Invites []*Invite `json:"result"`
+}
+
+// Does an invites response have more pages? A page smaller than a full
+// one is the last.
+func (self *InvitesResponse) HasMorePages() bool {
+	if !self.IsSuccess() {
+		return false
+	}
+	page_size := self.pageSize
+	return pageHasMore(len(self.Invites), &page_size)
+}
+
+// Invites response as a pretty json string
+func (self *InvitesResponse) String() string {
+	return asPrettyJSON(self.Invites)
+}
+
+// Get all teams in the account
+func (self *client) GetTeams() (*TeamsResponse, error) {
+	teams_resp := &TeamsResponse{}
+
+	err := self.httpGet(scopeAccountRead, "/teams", nil, &teams_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return teams_resp, nil
+}
+
+// Get a single team by its id
+func (self *client) GetTeam(id uint64) (*TeamResponse, error) {
+	team_resp := &TeamResponse{}
+
+	err := self.httpGet(scopeAccountRead, fmt.Sprintf("/team/%d", id), nil, &team_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return team_resp, nil
+}
+
+// Create a team
+func (self *client) CreateTeam(name string, access_level TeamAccessLevel) (*TeamResponse, error) {
+	if name == "" {
+		return nil, errors.New("Team name is required")
+	}
+
+	team := map[string]interface{}{
+		"name": name,
+	}
+	if access_level != "" {
+		team["access_level"] = access_level
+	}
+
+	team_resp := &TeamResponse{}
+
+	err := self.httpPost(scopeAccountWrite, "/teams", team, &team_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return team_resp, nil
+}
+
+// Delete a team by its id
+func (self *client) DeleteTeam(id uint64) error {
+	resp := &BaseAPIResponse{}
+	err := self.httpDelete(scopeAccountWrite, fmt.Sprintf("/team/%d", id), &resp)
+	return err
+}
+
+// Get first page of a team's users
+func (self *client) GetTeamUsers(team_id uint64) (*TeamUsersResponse, error) {
+	return self.GetTeamUsersWithPage(team_id, 1)
+}
+
+// Get a specific page of a team's users
+func (self *client) GetTeamUsersWithPage(team_id uint64, page uint64) (*TeamUsersResponse, error) {
+	if page == 0 {
+		return nil, errors.New("Page must be greater than 0")
+	}
+
+	query := url.Values{
+		"page": []string{fmt.Sprintf("%d", page)},
+	}
+
+	resp := &TeamUsersResponse{pageSize: TEAM_USERS_PAGE_SIZE, Page: page}
+
+	err := self.httpGet(scopeAccountRead, fmt.Sprintf("/team/%d/users", team_id), query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Add a user to a team
+func (self *client) AddTeamUser(team_id uint64, user_id uint64) error {
+	resp := &BaseAPIResponse{}
+	err := self.httpPut(scopeAccountWrite, fmt.Sprintf("/team/%d/user/%d", team_id, user_id), nil, &resp)
+	return err
+}
+
+// Remove a user from a team
+func (self *client) RemoveTeamUser(team_id uint64, user_id uint64) error {
+	resp := &BaseAPIResponse{}
+	err := self.httpDelete(scopeAccountWrite, fmt.Sprintf("/team/%d/user/%d", team_id, user_id), &resp)
+	return err
+}
+
+// Get the projects a team has access to
+func (self *client) GetTeamProjects(team_id uint64) (*TeamProjectsResponse, error) {
+	resp := &TeamProjectsResponse{}
+
+	err := self.httpGet(scopeAccountRead, fmt.Sprintf("/team/%d/projects", team_id), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Give a team access to a project
+func (self *client) AddTeamProject(team_id uint64, project_id uint64) error {
+	resp := &BaseAPIResponse{}
+	err := self.httpPut(scopeAccountWrite, fmt.Sprintf("/team/%d/project/%d", team_id, project_id), nil, &resp)
+	return err
+}
+
+// Remove a team's access to a project
+func (self *client) RemoveTeamProject(team_id uint64, project_id uint64) error {
+	resp := &BaseAPIResponse{}
+	err := self.httpDelete(scopeAccountWrite, fmt.Sprintf("/team/%d/project/%d", team_id, project_id), &resp)
+	return err
+}
+
+// Invite someone to a team by email
+func (self *client) InviteTeamUser(team_id uint64, email string) (*InviteResponse, error) {
+	if email == "" {
+		return nil, errors.New("Email is required")
+	}
+
+	invite_resp := &InviteResponse{}
+
+	err := self.httpPost(
+		scopeAccountWrite,
+		fmt.Sprintf("/team/%d/invites", team_id),
+		map[string]interface{}{
+			"email": email,
+		},
+		&invite_resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return invite_resp, nil
+}
+
+// Get a specific page of a team's invites
+func (self *client) GetTeamInvitesWithPage(team_id uint64, page uint64) (*InvitesResponse, error) {
+	if page == 0 {
+		return nil, errors.New("Page must be greater than 0")
+	}
+
+	query := url.Values{
+		"page": []string{fmt.Sprintf("%d", page)},
+	}
+
+	resp := &InvitesResponse{pageSize: TEAM_INVITES_PAGE_SIZE, Page: page}
+
+	err := self.httpGet(scopeAccountRead, fmt.Sprintf("/team/%d/invites", team_id), query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,148 @@
+package rollbar
+
+import (
+	"sync"
+	"time"
+)
+
+// Default number of telemetry entries kept and sent with notifications
+const DEFAULT_MAX_TELEMETRY = 50
+
+type TelemetryType string
+
+const (
+	TELEMETRY_LOG     TelemetryType = TelemetryType("log")
+	TELEMETRY_NETWORK TelemetryType = TelemetryType("network")
+	TELEMETRY_ERROR   TelemetryType = TelemetryType("error")
+	TELEMETRY_MANUAL  TelemetryType = TelemetryType("manual")
+)
+
+// Telemetry (breadcrumb) describing an event leading up to a notification
+type NotifierTelemetry struct {
+	// Required
+	Level       NotificationLevel `json:"level"`
+	Type        TelemetryType     `json:"type"`
+	Source      string            `json:"source"`
+	TimestampMS int64             `json:"timestamp_ms"`
+
+	// Contents depend on Type. For example, "log" should contain a
+	// "message" and "network" should contain "method", "url" and
+	// "status_code".
+	Body map[string]interface{} `json:"body"`
+}
+
+// Create a new telemetry entry
+func NewTelemetry(level NotificationLevel, typ TelemetryType, body map[string]interface{}) *NotifierTelemetry {
+	return &NotifierTelemetry{
+		Level:       level,
+		Type:        typ,
+		Source:      "server",
+		TimestampMS: time.Now().UnixNano() / int64(time.Millisecond),
+		Body:        body,
+	}
+}
+
+// Create a new "log" telemetry entry. Keys in 'custom' are added to the
+// body alongside the message.
+func NewLogTelemetry(level NotificationLevel, message string, custom CustomInfo) *NotifierTelemetry {
+	body := make(map[string]interface{}, len(custom)+1)
+	for k, v := range custom {
+		body[k] = v
+	}
+	body["message"] = message
+	return NewTelemetry(level, TELEMETRY_LOG, body)
+}
+
+// Create a new "network" telemetry entry for a call that started at
+// 'start' and finished at 'end'
+func NewNetworkTelemetry(level NotificationLevel, subtype string, method string, url string, status_code int, start time.Time, end time.Time) *NotifierTelemetry {
+	entry := NewTelemetry(
+		level,
+		TELEMETRY_NETWORK,
+		map[string]interface{}{
+			"subtype":            subtype,
+			"method":             method,
+			"url":                url,
+			"status_code":        status_code,
+			"start_timestamp_ms": start.UnixNano() / int64(time.Millisecond),
+			"end_timestamp_ms":   end.UnixNano() / int64(time.Millisecond),
+		},
+	)
+	return entry.SetTimestamp(start)
+}
+
+// Set the timestamp
+func (self *NotifierTelemetry) SetTimestamp(t time.Time) *NotifierTelemetry {
+	self.TimestampMS = t.UnixNano() / int64(time.Millisecond)
+	return self
+}
+
+// Body container holding telemetry. Embedded in every notification body.
+type notifierTelemetryBody struct {
+	Telemetry []*NotifierTelemetry `json:"telemetry,omitempty"`
+}
+
+func (self *notifierTelemetryBody) GetTelemetry() []*NotifierTelemetry {
+	return self.Telemetry
+}
+
+// The body doesn't know its notification, so each notification type
+// sets telemetry itself in order to return itself for chaining
+
+func (self *MessageNotification) SetTelemetry(telemetry []*NotifierTelemetry) Notification {
+	self.Telemetry = telemetry
+	return self
+}
+
+func (self *TraceNotification) SetTelemetry(telemetry []*NotifierTelemetry) Notification {
+	self.Telemetry = telemetry
+	return self
+}
+
+func (self *TraceChainNotification) SetTelemetry(telemetry []*NotifierTelemetry) Notification {
+	self.Telemetry = telemetry
+	return self
+}
+
+func (self *CrashReportNotification) SetTelemetry(telemetry []*NotifierTelemetry) Notification {
+	self.Telemetry = telemetry
+	return self
+}
+
+// Bounded queue of the most recent telemetry
+type telemetryQueue struct {
+	lock    sync.Mutex
+	entries []*NotifierTelemetry
+}
+
+func (self *telemetryQueue) add(entry *NotifierTelemetry, max int) {
+	if max < 0 {
+		return
+	}
+	if max == 0 {
+		max = DEFAULT_MAX_TELEMETRY
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.entries = append(self.entries, entry)
+	if len(self.entries) > max {
+		self.entries = append(self.entries[:0:0], self.entries[len(self.entries)-max:]...)
+	}
+}
+
+func (self *telemetryQueue) copy() []*NotifierTelemetry {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if len(self.entries) == 0 {
+		return nil
+	}
+	return append([]*NotifierTelemetry(nil), self.entries...)
+}
+
+// Add telemetry to be sent with future notifications
+func (self *client) AddTelemetry(entry *NotifierTelemetry) {
+	self.telemetry.add(entry, self.MaxTelemetry)
+}
@@ -0,0 +1,61 @@
+package rollbar
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Returned (wrapped) when a call needs a token scope that isn't configured
+var ErrNoAccessToken = errors.New("No access token configured")
+
+// Token scope an API call needs
+type tokenScope int
+
+const (
+	scopePostServerItem tokenScope = iota
+	scopeRead
+	scopeWrite
+	scopeAccountRead
+	scopeAccountWrite
+)
+
+func (self tokenScope) String() string {
+	switch self {
+	case scopePostServerItem:
+		return "post_server_item"
+	case scopeRead:
+		return "read"
+	case scopeWrite:
+		return "write"
+	case scopeAccountRead:
+		return "account read"
+	case scopeAccountWrite:
+		return "account write"
+	}
+	return "unknown"
+}
+
+// Get the token to use for a scope, falling back to the token given to
+// NewClient
+func (self *client) tokenFor(scope tokenScope) (string, error) {
+	var token string
+	switch scope {
+	case scopePostServerItem:
+		token = self.PostServerItemToken
+	case scopeRead:
+		token = self.ReadToken
+	case scopeWrite:
+		token = self.WriteToken
+	case scopeAccountRead:
+		token = self.AccountReadToken
+	case scopeAccountWrite:
+		token = self.AccountWriteToken
+	}
+	if token == "" {
+		token = self.accessToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("%w: need a token with %s scope", ErrNoAccessToken, scope)
+	}
+	return token, nil
+}
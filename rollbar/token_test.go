@@ -0,0 +1,120 @@
+package rollbar
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestScopedTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(c *client) error
+		want string
+	}{
+		{
+			"post_server_item",
+			func(c *client) error {
+				_, err := c.SendNotification(c.NewMessageNotification(LV_ERROR, "failed", nil))
+				return err
+			},
+			"post-token",
+		},
+		{
+			"notification's own token",
+			func(c *client) error {
+				notif := c.NewMessageNotification(LV_ERROR, "failed", nil)
+				notif.SetAccessToken("other-project")
+				_, err := c.SendNotification(notif)
+				return err
+			},
+			"other-project",
+		},
+		{
+			"read",
+			func(c *client) error {
+				_, err := c.GetItem(1)
+				return err
+			},
+			"read-token",
+		},
+		{
+			"write",
+			func(c *client) error { return c.SetItemStatus(1, ITEM_RESOLVED) },
+			"write-token",
+		},
+		{
+			"account read",
+			func(c *client) error {
+				_, err := c.GetProjects()
+				return err
+			},
+			"account-read-token",
+		},
+		{
+			"account write",
+			func(c *client) error { return c.DeleteProject(1) },
+			"account-write-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+				got = req.Header.Get(ACCESS_TOKEN_HEADER)
+				w.Write([]byte(`{"err": 0}`))
+			})
+			c.accessToken = ""
+			c.PostServerItemToken = "post-token"
+			c.ReadToken = "read-token"
+			c.WriteToken = "write-token"
+			c.AccountReadToken = "account-read-token"
+			c.AccountWriteToken = "account-write-token"
+
+			if err := tt.call(c); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected token %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScopedTokensFallBack(t *testing.T) {
+	var got string
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		got = req.Header.Get(ACCESS_TOKEN_HEADER)
+		w.Write([]byte(`{"err": 0}`))
+	})
+	c.WriteToken = "write-token"
+
+	if _, err := c.GetItem(1); err != nil || got != "test-token" {
+		t.Errorf("Expected the client's token for reads, got %q (%v)", got, err)
+	}
+	if err := c.SetItemStatus(1, ITEM_RESOLVED); err != nil || got != "write-token" {
+		t.Errorf("Expected the write token, got %q (%v)", got, err)
+	}
+}
+
+func TestScopedTokenMissing(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request without a token")
+	})
+	c.accessToken = ""
+	c.ReadToken = "read-token"
+
+	err := c.SetItemStatus(1, ITEM_RESOLVED)
+	if !errors.Is(err, ErrNoAccessToken) {
+		t.Fatalf("Expected ErrNoAccessToken, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "write scope") {
+		t.Errorf("Expected the missing scope in the error, got %q", err)
+	}
+
+	if _, err := c.SendNotification(c.NewMessageNotification(LV_ERROR, "failed", nil)); !errors.Is(err, ErrNoAccessToken) {
+		t.Errorf("Expected ErrNoAccessToken sending a notification, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package rollbar
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	tracebackStartRe = regexp.MustCompile(`(?m)^(panic|fatal error|runtime: out of memory|fatal: morestack on g0)(: (.*))?$`)
+	goroutineRe      = regexp.MustCompile(`(?m)^goroutine \d+ [^\n]*\[[^\]]*\]:$`)
+	frameFileRe      = regexp.MustCompile(`^\t(.*):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// Parse the output of an unrecovered Go panic or fatal error (as written
+// to stderr by the runtime) into a trace. The first goroutine after the
+// panic message is used, which is the one that panicked. Frames are
+// ordered oldest first, as rollbar expects. Returns nil if no traceback is
+// found.
+func ParseGoTraceback(output string) *NotifierTrace {
+	loc := tracebackStartRe.FindStringSubmatchIndex(output)
+	if loc == nil {
+		return nil
+	}
+
+	class := output[loc[2]:loc[3]]
+	message := ""
+	if loc[6] >= 0 {
+		message = output[loc[6]:loc[7]]
+	}
+	if class == "runtime: out of memory" || class == "fatal: morestack on g0" {
+		message = class
+		class = "fatal error"
+	}
+	message = strings.TrimSuffix(message, " [recovered]")
+
+	rest := output[loc[1]:]
+	gr_loc := goroutineRe.FindStringIndex(rest)
+	if gr_loc == nil {
+		return nil
+	}
+
+	// Anything between the message and the goroutine (nested panics,
+	// "[signal SIGSEGV..." lines) is kept as the description
+	description := strings.TrimSpace(rest[:gr_loc[0]])
+
+	trace := &NotifierTrace{
+		Exception: &NotifierException{
+			Class:       class,
+			Message:     message,
+			Description: description,
+		},
+		Frames: parseGoroutineFrames(rest[gr_loc[1]:]),
+	}
+
+	return trace
+}
+
+// Parse function/file line pairs until the end of the goroutine's stack
+func parseGoroutineFrames(stack string) []*NotifierFrame {
+	frames := make([]*NotifierFrame, 0, 32)
+	lines := strings.Split(strings.TrimLeft(stack, "\r\n"), "\n")
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		fn_line := strings.TrimRight(lines[i], "\r")
+		if fn_line == "" || strings.HasPrefix(fn_line, "goroutine ") {
+			break
+		}
+
+		m := frameFileRe.FindStringSubmatch(strings.TrimRight(lines[i+1], "\r"))
+		if m == nil {
+			break
+		}
+		line_no, _ := strconv.Atoi(m[2])
+
+		frames = append(
+			frames,
+			&NotifierFrame{
+				Filename: m[1],
+				Line:     line_no,
+				Method:   goFrameFunction(fn_line),
+			},
+		)
+	}
+
+	// Goroutine stacks list the most recent call first, but rollbar wants
+	// it last
+	slices.Reverse(frames)
+	return frames
+}
+
+// Strip arguments from a traceback function line. e.g.
+// "main.(*T).run(0xc000010000, {0x1, 0x2})" becomes "main.(*T).run" and
+// "created by main.main in goroutine 1" becomes "main.main".
+func goFrameFunction(line string) string {
+	if strings.HasPrefix(line, "created by ") {
+		line = strings.TrimPrefix(line, "created by ")
+		if idx := strings.Index(line, " in goroutine "); idx >= 0 {
+			line = line[:idx]
+		}
+		return line
+	}
+	if strings.HasSuffix(line, ")") {
+		// Don't strip receivers such as (*T)
+		if idx := strings.LastIndex(line, "("); idx > 0 && line[idx-1] != '.' {
+			line = line[:idx]
+		}
+	}
+	return line
+}
@@ -0,0 +1,49 @@
+package rollbar
+
+import (
+	"fmt"
+	"testing"
+)
+
+const testTraceback = `some output
+panic: runtime error: index out of range [5] with length 3
+
+goroutine 1 [running]:
+main.(*Server).handle(0xc000010000, {0x1, 0x2})
+	/src/app/server.go:42 +0x1d
+main.process(...)
+	/src/app/process.go:17
+main.main()
+	/src/app/main.go:9 +0x25
+exit status 2
+`
+
+func TestParseGoTraceback(t *testing.T) {
+	trace := ParseGoTraceback(testTraceback)
+	if trace == nil {
+		t.Fatalf("Expected a trace")
+	}
+
+	if trace.Exception.Class != "panic" || trace.Exception.Message != "runtime error: index out of range [5] with length 3" {
+		t.Errorf("Unexpected exception: %+v", trace.Exception)
+	}
+
+	// Oldest first
+	want := []string{
+		"/src/app/main.go:9 main.main",
+		"/src/app/process.go:17 main.process",
+		"/src/app/server.go:42 main.(*Server).handle",
+	}
+	if len(trace.Frames) != len(want) {
+		t.Fatalf("Expected %d frames, got %d", len(want), len(trace.Frames))
+	}
+	for i, frame := range trace.Frames {
+		if got := fmt.Sprintf("%s:%d %s", frame.Filename, frame.Line, frame.Method); got != want[i] {
+			t.Errorf("Frame %d: expected %s, got %s", i, want[i], got)
+		}
+	}
+
+	if ParseGoTraceback("no panic here") != nil {
+		t.Errorf("Expected nil without a traceback")
+	}
+}
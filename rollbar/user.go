@@ -0,0 +1,75 @@
+package rollbar
+
+import "fmt"
+
+// User object as returned from API
+type User struct {
+	ID           uint64 `json:"id"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	EmailEnabled bool   `json:"email_enabled"`
+}
+
+// String representation in pretty JSON form
+func (self *User) String() string {
+	return self.AsPrettyJSON()
+}
+
+// User as json string
+func (self *User) AsJSON() string {
+	return asJSON(self)
+}
+
+// User as pretty json
+func (self *User) AsPrettyJSON() string {
+	return asPrettyJSON(self)
+}
+
+// Full API response for a single user
+type UserResponse struct {
+	BaseAPIResponse
+	*User `json:"result"`
+}
+
+// Container for multiple users
+type UsersResult struct {
+	Users []*User `json:"users"`
+}
+
+// Full API response for multiple users
+type UsersResponse struct {
+	BaseAPIResponse
+	*UsersResult `json:"result"`
+}
+
+// Users response as a pretty json string
+func (self *UsersResponse) String() string {
+	if self.UsersResult == nil {
+		return "[]"
+	}
+	return asPrettyJSON(self.Users)
+}
+
+// Get all users in the account
+func (self *client) GetUsers() (*UsersResponse, error) {
+	users_resp := &UsersResponse{}
+
+	err := self.httpGet(scopeAccountRead, "/users", nil, &users_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return users_resp, nil
+}
+
+// Get a single user by its id
+func (self *client) GetUser(id uint64) (*UserResponse, error) {
+	user_resp := &UserResponse{}
+
+	err := self.httpGet(scopeAccountRead, fmt.Sprintf("/user/%d", id), nil, &user_resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return user_resp, nil
+}
@@ -0,0 +1,234 @@
+package rollbar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	DEFAULT_WATCH_INTERVAL    = 10 * time.Second
+	DEFAULT_WATCH_MAX_BACKOFF = 5 * time.Minute
+	DEFAULT_WATCH_MAX_CATCHUP = 500
+)
+
+// What WatchOccurrences() watches. Empty fields are not filtered on.
+type WatchFilter struct {
+	// Only watch this item's occurrences (by item id -- NOT the counter)
+	ItemID uint64
+
+	Levels       []NotificationLevel
+	Environments []string
+
+	// Only send occurrences with a higher id. 0 means only occurrences
+	// that arrive after watching starts.
+	SinceID uint64
+
+	// Time between polls. Defaults to DEFAULT_WATCH_INTERVAL
+	Interval time.Duration
+
+	// Longest wait between polls while the API is returning errors.
+	// Defaults to DEFAULT_WATCH_MAX_BACKOFF
+	MaxBackoff time.Duration
+
+	// Most occurrences fetched by a single poll, to bound catching up
+	// after a burst or a long outage. If more arrived, a *WatchGapError
+	// is sent. Defaults to DEFAULT_WATCH_MAX_CATCHUP
+	MaxCatchUp int
+}
+
+// Sent as a WatchEvent's Err when more than MaxCatchUp occurrences arrived
+// between polls. Occurrences with ids between AfterID and BeforeID (both
+// exclusive) were skipped; the newer ones are still sent.
+type WatchGapError struct {
+	AfterID  uint64
+	BeforeID uint64
+}
+
+func (self *WatchGapError) Error() string {
+	return fmt.Sprintf(
+		"Skipped occurrences after id %d and before id %d: more than MaxCatchUp arrived between polls",
+		self.AfterID,
+		self.BeforeID,
+	)
+}
+
+func (self *WatchFilter) matches(occur *Occurrence) bool {
+	if len(self.Levels) > 0 {
+		found := false
+		for _, level := range self.Levels {
+			if string(level) == occur.Data.Level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(self.Environments) > 0 {
+		found := false
+		for _, env := range self.Environments {
+			if env == occur.Data.Environment {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Sent by WatchOccurrences(). Either Occurrence or Err is set. Errors
+// don't stop watching.
+type WatchEvent struct {
+	Occurrence *Occurrence
+	Err        error
+}
+
+// Poll for new occurrences, sending them oldest first. The channel is
+// closed once ctx is done.
+func (self *client) WatchOccurrences(ctx context.Context, filter *WatchFilter) <-chan *WatchEvent {
+	opts := WatchFilter{}
+	if filter != nil {
+		opts = *filter
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DEFAULT_WATCH_INTERVAL
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DEFAULT_WATCH_MAX_BACKOFF
+	}
+	if opts.MaxBackoff < opts.Interval {
+		opts.MaxBackoff = opts.Interval
+	}
+	if opts.MaxCatchUp <= 0 {
+		opts.MaxCatchUp = DEFAULT_WATCH_MAX_CATCHUP
+	}
+
+	ch := make(chan *WatchEvent)
+
+	go func() {
+		defer close(ch)
+
+		send := func(event *WatchEvent) bool {
+			select {
+			case ch <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		last_id := opts.SinceID
+		started := last_id != 0
+		wait := time.Duration(0)
+
+		for {
+			if wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+
+			occurs, gap, err := self.pollOccurrences(ctx, &opts, last_id, !started)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if !send(&WatchEvent{Err: err}) {
+					return
+				}
+				if wait *= 2; wait < opts.Interval {
+					wait = opts.Interval
+				} else if wait > opts.MaxBackoff {
+					wait = opts.MaxBackoff
+				}
+				continue
+			}
+			wait = opts.Interval
+
+			if gap != nil && !send(&WatchEvent{Err: gap}) {
+				return
+			}
+
+			for _, occur := range occurs {
+				if occur.ID > last_id {
+					last_id = occur.ID
+				}
+				if started && opts.matches(occur) {
+					if !send(&WatchEvent{Occurrence: occur}) {
+						return
+					}
+				}
+			}
+			// The first poll only finds where to start from
+			started = true
+		}
+	}()
+
+	return ch
+}
+
+// Get occurrences newer than 'last_id', oldest first, paging back until
+// 'last_id' is reached. If more than MaxCatchUp are newer, only the newest
+// are returned, along with a gap error. On the first poll, just the newest
+// is fetched.
+func (self *client) pollOccurrences(ctx context.Context, opts *WatchFilter, last_id uint64, first bool) ([]*Occurrence, *WatchGapError, error) {
+	// One more than is kept, to tell if any were skipped
+	pager_opts := &PagerOptions{MaxItems: opts.MaxCatchUp + 1}
+	if first {
+		pager_opts.MaxItems = 1
+	}
+
+	fetch := func(page uint64) ([]*Occurrence, bool, error) {
+		resp := &OccurrencesResponse{
+			rollbar:  self,
+			itemID:   opts.ItemID,
+			pageSize: OCCURRENCES_PAGE_SIZE,
+			Page:     page,
+		}
+		var err error
+		if opts.ItemID != 0 {
+			resp, err = self.getItemOccurrences(ctx, opts.ItemID, resp)
+		} else {
+			resp, err = self.getOccurrences(ctx, resp)
+		}
+		if err != nil || resp.OccurrencesResult == nil {
+			return nil, false, err
+		}
+		return resp.Occurrences, resp.HasMorePages(), nil
+	}
+
+	var occurs []*Occurrence
+	for occur, err := range newPager(fetch, occurrenceTimestamp, pager_opts).All() {
+		if err != nil {
+			return nil, nil, err
+		}
+		if occur.ID <= last_id {
+			break
+		}
+		occurs = append(occurs, occur)
+	}
+
+	var gap *WatchGapError
+	if !first && len(occurs) > opts.MaxCatchUp {
+		occurs = occurs[:opts.MaxCatchUp]
+		gap = &WatchGapError{
+			AfterID:  last_id,
+			BeforeID: occurs[len(occurs)-1].ID,
+		}
+	}
+
+	sort.Slice(occurs, func(i, j int) bool {
+		return occurs[i].ID < occurs[j].ID
+	})
+	return occurs, gap, nil
+}
@@ -0,0 +1,129 @@
+package rollbar_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/comstud/go-rollbar/rollbar"
+	"github.com/comstud/go-rollbar/rollbar/rollbartest"
+)
+
+func postMessages(t *testing.T, client rollbar.Client, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		notif := client.NewMessageNotification(rollbar.LV_ERROR, fmt.Sprintf("message %d", i), nil)
+		if _, err := client.SendNotification(notif); err != nil {
+			t.Fatalf("Error posting: %s", err)
+		}
+	}
+}
+
+// Receive 'n' events, failing the test if they don't arrive in time
+func receiveEvents(t *testing.T, ch <-chan *rollbar.WatchEvent, n int) []*rollbar.WatchEvent {
+	t.Helper()
+	var events []*rollbar.WatchEvent
+	timeout := time.After(5 * time.Second)
+	for len(events) < n {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				t.Fatalf("Channel closed after %d events, expected %d", len(events), n)
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatalf("Timed out after %d events, expected %d", len(events), n)
+		}
+	}
+	return events
+}
+
+func TestWatchOccurrencesCatchUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		posted     int
+		maxCatchUp int
+		wantGap    bool
+		wantOccurs int
+	}{
+		{"single page", 5, 50, false, 5},
+		{"several pages", 2*rollbartest.DEFAULT_PAGE_SIZE + 3, 100, false, 2*rollbartest.DEFAULT_PAGE_SIZE + 3},
+		{"exactly max catch up", 5, 5, false, 5},
+		{"gap", 12, 5, true, 5},
+		{"gap over pages", 3 * rollbartest.DEFAULT_PAGE_SIZE, rollbartest.DEFAULT_PAGE_SIZE + 1, true, rollbartest.DEFAULT_PAGE_SIZE + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := rollbartest.NewServer()
+			defer server.Close()
+			client := server.NewClient()
+
+			postMessages(t, client, 1)
+			since := server.Occurrences()[0].ID
+			postMessages(t, client, tt.posted)
+			all := server.Occurrences()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ch := client.WatchOccurrences(ctx, &rollbar.WatchFilter{
+				SinceID:    since,
+				Interval:   time.Hour,
+				MaxCatchUp: tt.maxCatchUp,
+			})
+
+			n := tt.wantOccurs
+			if tt.wantGap {
+				n++
+			}
+			events := receiveEvents(t, ch, n)
+
+			if tt.wantGap {
+				var gap *rollbar.WatchGapError
+				if !errors.As(events[0].Err, &gap) {
+					t.Fatalf("Expected a gap error first, got %+v", events[0])
+				}
+				first_kept := all[len(all)-tt.wantOccurs].ID
+				if gap.AfterID != since || gap.BeforeID != first_kept {
+					t.Errorf("Expected gap (%d, %d), got (%d, %d)", since, first_kept, gap.AfterID, gap.BeforeID)
+				}
+				events = events[1:]
+			}
+
+			// The newest, oldest first
+			want := all[len(all)-tt.wantOccurs:]
+			for i, event := range events {
+				if event.Err != nil {
+					t.Fatalf("Unexpected error: %s", event.Err)
+				}
+				if event.Occurrence.ID != want[i].ID {
+					t.Fatalf("Event %d: expected occurrence %d, got %d", i, want[i].ID, event.Occurrence.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchOccurrencesCancelDuringPoll(t *testing.T) {
+	server := rollbartest.NewServer()
+	defer server.Close()
+	server.SetLatency(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := server.NewClient().WatchOccurrences(ctx, nil)
+
+	// Let the first poll start
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Errorf("Expected no events")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Channel wasn't closed after cancelling a slow poll")
+	}
+}